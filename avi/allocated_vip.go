@@ -0,0 +1,89 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+// withAllocatedVip adds the addresses and fqdns allocated to the vips of a
+// vsvip or virtual service to the schema s.
+func withAllocatedVip(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for _, k := range []string{"allocated_ipv4", "allocated_ipv6", "allocated_fip"} {
+		s[k] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	s["allocated_dns_fqdns"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	return s
+}
+
+// setAllocatedVip sets the allocated attributes of d from its vip and
+// dns_info. The vips of a virtual service that refers to a vsvip are read
+// from the vsvip, through the object cache when it is enabled.
+func setAllocatedVip(d *schema.ResourceData, meta interface{}) error {
+	var obj struct {
+		Vip     []aviVip `json:"vip"`
+		DNSInfo []struct {
+			Fqdn string `json:"fqdn"`
+		} `json:"dns_info"`
+		Fqdn string `json:"fqdn"`
+	}
+	state := map[string]interface{}{"vip": d.Get("vip"), "dns_info": d.Get("dns_info")}
+	if fqdn, ok := d.GetOk("fqdn"); ok {
+		state["fqdn"] = fqdn
+	}
+	if err := schemaToModel(state, &obj); err != nil {
+		return err
+	}
+	fqdn := obj.Fqdn
+	if ref, ok := d.GetOk("vsvip_ref"); ok && ref.(string) != "" {
+		// fields the vsvip does not set keep the values of the virtual service.
+		// A vsvip that can not be read, for example because it was deleted,
+		// leaves them all.
+		if vsvip, err := apiReadObjectByUUID(meta, "vsvip", UUIDFromID(ref.(string))); err != nil {
+			log.Printf("[WARN] setAllocatedVip could not read vsvip %v: %v\n", ref, err)
+		} else if err := jsonToModel(vsvip, &obj); err != nil {
+			return err
+		}
+	}
+	fqdns := []string{}
+	seen := map[string]bool{"": true}
+	candidates := []string{fqdn}
+	for _, info := range obj.DNSInfo {
+		candidates = append(candidates, info.Fqdn)
+	}
+	for _, f := range candidates {
+		if !seen[f] {
+			seen[f] = true
+			fqdns = append(fqdns, f)
+		}
+	}
+	// the first vip with an address of a kind provides it.
+	allocated := map[string]string{}
+	for _, vip := range obj.Vip {
+		for k, addr := range map[string]*aviIPAddr{
+			"allocated_ipv4": vip.IPAddress,
+			"allocated_ipv6": vip.IP6Address,
+			"allocated_fip":  vip.FloatingIP,
+		} {
+			if _, ok := allocated[k]; !ok && addr != nil && addr.Addr != "" {
+				allocated[k] = addr.Addr
+			}
+		}
+	}
+	for _, k := range []string{"allocated_ipv4", "allocated_ipv6", "allocated_fip"} {
+		d.Set(k, allocated[k])
+	}
+	d.Set("allocated_dns_fqdns", fqdns)
+	return nil
+}
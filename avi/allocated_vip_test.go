@@ -0,0 +1,96 @@
+package avi
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestAllocatedVipRead(t *testing.T) {
+	vsvip := `{"uuid": "vsvip-1", "name": "web-vip", "url": "https://localhost/api/vsvip/vsvip-1",
+		"vip": [{"vip_id": "0", "auto_allocate_ip": true, "auto_allocate_floating_ip": true,
+			"ip_address": {"addr": "10.10.1.15", "type": "V4"},
+			"floating_ip": {"addr": "192.0.2.15", "type": "V4"}},
+			{"vip_id": "1", "ip6_address": {"addr": "2001:db8::15", "type": "V6"}}],
+		"dns_info": [{"fqdn": "web.example.com"}, {"fqdn": "www.example.com"}]}`
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/vsvip/vsvip-1": vsvip,
+		"/api/virtualservice/vs-1": `{"uuid": "vs-1", "name": "web", "url": "https://localhost/api/virtualservice/vs-1",
+			"vsvip_ref": "https://localhost/api/vsvip/vsvip-1", "fqdn": "web.example.com"}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	expected := map[string]interface{}{
+		"allocated_ipv4":        "10.10.1.15",
+		"allocated_ipv6":        "2001:db8::15",
+		"allocated_fip":         "192.0.2.15",
+		"allocated_dns_fqdns.#": 2,
+		"allocated_dns_fqdns.0": "web.example.com",
+		"allocated_dns_fqdns.1": "www.example.com",
+	}
+	d := resourceAviVsVip().TestResourceData()
+	d.SetId("https://localhost/api/vsvip/vsvip-1")
+	if err := ResourceAviVsVipRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("vsvip: %v = %v, expected %v", k, actual, v)
+		}
+	}
+
+	d = dataSourceAviVirtualService().TestResourceData()
+	d.Set("uuid", "vs-1")
+	if err := ResourceAviVirtualServiceRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("virtualservice: %v = %v, expected %v", k, actual, v)
+		}
+	}
+}
+
+func TestAllocatedVipReadCached(t *testing.T) {
+	requests, vsvipReads := 0, 0
+	vsvips := []map[string]interface{}{{"uuid": "vsvip-1", "name": "web-vip", "url": "https://localhost/api/vsvip/vsvip-1",
+		"vip": []map[string]interface{}{{"vip_id": "0", "ip_address": map[string]string{"addr": "10.10.1.15", "type": "V4"}}}}}
+	collection := testCollectionHandler(t, vsvips, &requests)
+	objects := testJSONHandler(map[string]string{
+		"/api/virtualservice/vs-1": `{"uuid": "vs-1", "name": "web", "url": "https://localhost/api/virtualservice/vs-1",
+			"vsvip_ref": "https://localhost/api/vsvip/vsvip-1"}`,
+		"/api/virtualservice/vs-2": `{"uuid": "vs-2", "name": "old", "url": "https://localhost/api/virtualservice/vs-2",
+			"vsvip_ref": "https://localhost/api/vsvip/vsvip-2"}`,
+	})
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/vsvip":
+			collection(w, r)
+		case strings.HasPrefix(r.URL.Path, "/api/vsvip/"):
+			vsvipReads++
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			objects(w, r)
+		}
+	})
+	defer server.Close()
+	testProviderSettings(client, 0).cache = newObjectCache()
+
+	// the vsvip is served from the cached collection.
+	d := dataSourceAviVirtualService().TestResourceData()
+	d.Set("uuid", "vs-1")
+	if err := ResourceAviVirtualServiceRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ip := d.Get("allocated_ipv4"); ip != "10.10.1.15" || vsvipReads != 0 {
+		t.Errorf("allocated_ipv4 = %v with %v vsvip reads, expected 10.10.1.15 from the cache", ip, vsvipReads)
+	}
+
+	// a deleted vsvip does not fail the read of the virtual service.
+	d = dataSourceAviVirtualService().TestResourceData()
+	d.Set("uuid", "vs-2")
+	if err := ResourceAviVirtualServiceRead(d, client); err != nil || d.Id() == "" {
+		t.Errorf("id = %v, err = %v, expected the virtual service to be read", d.Id(), err)
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"errors"
+	"github.com/mitchellh/copystructure"
+	"log"
+	"sync"
+)
+
+// objectCache keeps whole collections of objects in memory so that refreshing
+// a large configuration makes one paged collection read per object type
+// instead of one GET per resource. It is enabled per provider instance with
+// enable_cache, and any write through the provider drops the written object
+// from the cached collection of its type.
+type objectCache struct {
+	lock  sync.Mutex
+	types map[string]*objectCacheEntry
+	// pages is the number of collection pages read to fill the cache.
+	pages int
+	// hits is the number of reads served from memory.
+	hits int
+}
+
+// objectCacheEntry is the cached collection of one object type. loaded is
+// closed once objs and err are set so concurrent readers of a type wait for a
+// single collection read. objs is accessed under the lock of the cache once
+// loaded, as writes evict objects from it.
+type objectCacheEntry struct {
+	loaded chan struct{}
+	objs   map[string]map[string]interface{}
+	err    error
+}
+
+func newObjectCache() *objectCache {
+	return &objectCache{types: map[string]*objectCacheEntry{}}
+}
+
+// getObjectCache returns the cache of the provider that created meta or nil
+// when caching is disabled.
+func getObjectCache(meta interface{}) *objectCache {
+	return getProviderSettings(meta).cache
+}
+
+// entry returns the cached collection of objType, reading it from the
+// controller on first use.
+func (c *objectCache) entry(meta interface{}, objType string) *objectCacheEntry {
+	c.lock.Lock()
+	entry, ok := c.types[objType]
+	if ok {
+		c.lock.Unlock()
+		<-entry.loaded
+		return entry
+	}
+	entry = &objectCacheEntry{loaded: make(chan struct{})}
+	c.types[objType] = entry
+	c.lock.Unlock()
+
+	objs := map[string]map[string]interface{}{}
+	pages, err := apiCollectionIteratePages(meta, "api/"+objType+"?skip_default=true",
+		func(obj map[string]interface{}) error {
+			if uuid, ok := obj["uuid"].(string); ok {
+				objs[uuid] = obj
+			}
+			return nil
+		})
+	entry.objs = objs
+	entry.err = err
+	close(entry.loaded)
+
+	c.lock.Lock()
+	c.pages += pages
+	if err != nil && c.types[objType] == entry {
+		// do not keep a failed read around, the next reader retries.
+		delete(c.types, objType)
+	}
+	c.lock.Unlock()
+	if err != nil {
+		log.Printf("[ERROR] objectCache failed to read %v collection: %v\n", objType, err)
+	} else {
+		log.Printf("[DEBUG] objectCache loaded %v objects of type %v in %v API calls\n", len(objs), objType, pages)
+	}
+	return entry
+}
+
+// Get returns a copy of the cached object of objType with the given uuid. ok
+// is false when the collection could not be read or does not contain the
+// uuid, in which case the caller should read the object from the controller.
+func (c *objectCache) Get(meta interface{}, objType string, uuid string) (map[string]interface{}, bool) {
+	entry := c.entry(meta, objType)
+	if entry.err != nil {
+		return nil, false
+	}
+	c.lock.Lock()
+	obj, ok := entry.objs[uuid]
+	c.lock.Unlock()
+	if !ok {
+		return nil, false
+	}
+	c.hit(objType)
+	return copyCachedObject(obj), true
+}
+
+// Find returns copies of the cached objects of objType with the given name,
+// restricted to the cloud with cloudUUID when it is set. ok is false when the
+// collection could not be read.
+func (c *objectCache) Find(meta interface{}, objType string, name string, cloudUUID string) ([]map[string]interface{}, bool) {
+	entry := c.entry(meta, objType)
+	if entry.err != nil {
+		return nil, false
+	}
+	var objs []map[string]interface{}
+	c.lock.Lock()
+	for _, obj := range entry.objs {
+		if obj["name"] != name {
+			continue
+		}
+		if cloudUUID != "" {
+			if cloudRef, ok := obj["cloud_ref"].(string); !ok || UUIDFromID(cloudRef) != cloudUUID {
+				continue
+			}
+		}
+		objs = append(objs, copyCachedObject(obj))
+	}
+	c.lock.Unlock()
+	if len(objs) > 0 {
+		c.hit(objType)
+	}
+	return objs, true
+}
+
+// Evict drops the object of objType with the given uuid from the cache after
+// it was written, so that it is read from the controller again. The whole
+// collection is dropped when uuid is not known or the collection is still
+// being read, as the read may have fetched the object before the write.
+func (c *objectCache) Evict(objType string, uuid string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	entry, ok := c.types[objType]
+	if !ok {
+		return
+	}
+	loaded := false
+	select {
+	case <-entry.loaded:
+		loaded = true
+	default:
+	}
+	if uuid == "" || !loaded {
+		log.Printf("[DEBUG] objectCache invalidating %v\n", objType)
+		delete(c.types, objType)
+		return
+	}
+	log.Printf("[DEBUG] objectCache evicting %v %v\n", objType, uuid)
+	delete(entry.objs, uuid)
+}
+
+func (c *objectCache) hit(objType string) {
+	c.lock.Lock()
+	c.hits++
+	hits, pages := c.hits, c.pages
+	c.lock.Unlock()
+	log.Printf("[DEBUG] objectCache served %v from memory: %v reads served by %v collection API calls, %v API calls saved\n",
+		objType, hits, pages, hits-pages)
+}
+
+// copyCachedObject returns a deep copy of obj, since ApiRead fills defaults
+// into the objects it reads.
+func copyCachedObject(obj map[string]interface{}) map[string]interface{} {
+	cobj, err := copystructure.Copy(obj)
+	if err != nil {
+		log.Printf("[ERROR] objectCache failed to copy %v: %v\n", obj["uuid"], err)
+		return nil
+	}
+	return cobj.(map[string]interface{})
+}
+
+// apiReadObjectByName is ApiGetObjectByName served from the object cache
+// when it is enabled. Names that are not cached are looked up on the
+// controller.
+func apiReadObjectByName(meta interface{}, objType string, name string, cloudUUID string) (interface{}, error) {
+	if cache := getObjectCache(meta); cache != nil {
+		objs, ok := cache.Find(meta, objType, name, cloudUUID)
+		if ok && len(objs) == 1 && objs[0] != nil {
+			return objs[0], nil
+		} else if ok && len(objs) > 1 {
+			return nil, errors.New("More than one object of type " + objType + " with name " + name + " is found")
+		}
+	}
+	return ApiGetObjectByName(meta, objType, name, cloudUUID)
+}
+
+// apiReadObjectByUUID reads the object of objType with the given uuid, from
+// the object cache when it is enabled.
+func apiReadObjectByUUID(meta interface{}, objType string, uuid string) (interface{}, error) {
+	if cache := getObjectCache(meta); cache != nil {
+		if obj, ok := cache.Get(meta, objType, uuid); ok && obj != nil {
+			return obj, nil
+		}
+	}
+	var obj interface{}
+	path := "api/" + objType + "/" + uuid
+	if err := aviSession(meta).Get(path, &obj); err != nil {
+		log.Printf("[ERROR] apiReadObjectByUUID %v in GET of %v\n", err, path)
+		return nil, err
+	}
+	return obj, nil
+}
+
+// invalidateObjectCache evicts the object of objType with the given uuid, if
+// cached, after the provider wrote it. Writes made in another tenant evict
+// the object from the cache of the provider as well, which may hold it when
+// it is visible in the tenant of the provider.
+func invalidateObjectCache(meta interface{}, objType string, uuid string) {
+	if m, ok := meta.(*tenantMeta); ok {
+		meta = m.client
+	}
+	if cache := getObjectCache(meta); cache != nil {
+		cache.Evict(objType, uuid)
+	}
+}
@@ -0,0 +1,108 @@
+package avi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// testCacheHandler serves pools both as a collection and by uuid and counts
+// the requests of each kind.
+func testCacheHandler(t *testing.T, pools []map[string]interface{}, collectionRequests *int, objectRequests *int) http.HandlerFunc {
+	collection := testCollectionHandler(t, pools, collectionRequests)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/pool" {
+			collection(w, r)
+			return
+		}
+		*objectRequests++
+		uuid := strings.TrimPrefix(r.URL.Path, "/api/pool/")
+		for _, pool := range pools {
+			if pool["uuid"] == uuid {
+				json.NewEncoder(w).Encode(pool)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("{}"))
+	}
+}
+
+func testCacheReadPool(t *testing.T, meta interface{}, uuid string) {
+	d := resourceAviPool().TestResourceData()
+	d.SetId("https://localhost/api/pool/" + uuid)
+	if err := ApiRead(d, meta, "pool", ResourcePoolSchema()); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("uuid") != uuid {
+		t.Fatalf("expected pool %v, got %v", uuid, d.Get("uuid"))
+	}
+}
+
+func TestObjectCacheServesReads(t *testing.T) {
+	collectionRequests, objectRequests := 0, 0
+	pools := testPools(5)
+	client, server := newTestAviClient(t, testCacheHandler(t, pools, &collectionRequests, &objectRequests))
+	defer server.Close()
+	testProviderSettings(client, 2).cache = newObjectCache()
+
+	for _, pool := range pools {
+		testCacheReadPool(t, client, pool["uuid"].(string))
+	}
+	if collectionRequests != 3 || objectRequests != 0 {
+		t.Fatalf("expected 3 collection and 0 object requests, got %d and %d",
+			collectionRequests, objectRequests)
+	}
+
+	// a write evicts only the written object, which is read from the
+	// controller, in the tenant of the provider or another one.
+	invalidateObjectCache(client, "pool", "pool-1")
+	invalidateObjectCache(withTenant(client, "dev"), "pool", "pool-2")
+	for _, pool := range pools {
+		testCacheReadPool(t, client, pool["uuid"].(string))
+	}
+	if collectionRequests != 3 || objectRequests != 2 {
+		t.Fatalf("expected 3 collection and 2 object requests, got %d and %d",
+			collectionRequests, objectRequests)
+	}
+	objectRequests = 0
+
+	// an unknown uuid drops the collection and the next read reloads it.
+	invalidateObjectCache(client, "pool", "")
+	testCacheReadPool(t, client, "pool-1")
+	if collectionRequests != 6 {
+		t.Fatalf("expected the collection to be read again, got %d requests", collectionRequests)
+	}
+
+	// objects missing from the collection are read from the controller.
+	pools = append(pools, map[string]interface{}{"uuid": "pool-new", "name": "new"})
+	client2, server2 := newTestAviClient(t, testCacheHandler(t, pools, &collectionRequests, &objectRequests))
+	defer server2.Close()
+	cache := newObjectCache()
+	testProviderSettings(client2, 100).cache = cache
+	cache.types["pool"] = &objectCacheEntry{loaded: make(chan struct{}), objs: map[string]map[string]interface{}{}}
+	close(cache.types["pool"].loaded)
+	testCacheReadPool(t, client2, "pool-new")
+	if objectRequests != 1 {
+		t.Fatalf("expected 1 object request, got %d", objectRequests)
+	}
+}
+
+func TestObjectCacheReturnsCopies(t *testing.T) {
+	collectionRequests, objectRequests := 0, 0
+	client, server := newTestAviClient(t, testCacheHandler(t, testPools(1), &collectionRequests, &objectRequests))
+	defer server.Close()
+	cache := newObjectCache()
+	testProviderSettings(client, 2).cache = cache
+
+	obj, ok := cache.Get(client, "pool", "pool-0")
+	if !ok {
+		t.Fatalf("pool-0 not found in cache")
+	}
+	obj["name"] = "changed"
+	objs, ok := cache.Find(client, "pool", "pool0", "")
+	if !ok || len(objs) != 1 {
+		t.Fatalf("expected pool0 to be found by name, got %v", objs)
+	}
+}
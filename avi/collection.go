@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// defaultApiPageSize is the number of objects requested per collection page
+// when the provider does not set api_page_size.
+const defaultApiPageSize = 100
+
+// aviCollectionPage is one page of a collection GET such as api/pool.
+type aviCollectionPage struct {
+	Count   int           `json:"count"`
+	Next    string        `json:"next"`
+	Results []interface{} `json:"results"`
+}
+
+// ApiCollectionIterate reads the collection at path one page at a time and
+// calls fn for every object. The controller paginates collections, so the next
+// link of each page is followed until the last page. Iteration stops at the
+// first error returned by the API or by fn.
+func ApiCollectionIterate(meta interface{}, path string, fn func(obj map[string]interface{}) error) error {
+	_, err := apiCollectionIteratePages(meta, path, fn)
+	return err
+}
+
+// apiCollectionIteratePages is ApiCollectionIterate that also returns the
+// number of pages read from the controller.
+func apiCollectionIteratePages(meta interface{}, path string, fn func(obj map[string]interface{}) error) (int, error) {
+	sess := aviSession(meta)
+	pages := 0
+	path = collectionPagePath(path, getProviderSettings(meta).pageSize)
+	for path != "" {
+		var page aviCollectionPage
+		log.Printf("[DEBUG] ApiCollectionIterate reading page %v\n", path)
+		pages++
+		if err := sess.Get(path, &page); err != nil {
+			log.Printf("[ERROR] ApiCollectionIterate %v in GET of path %v\n", err, path)
+			return pages, err
+		}
+		for _, result := range page.Results {
+			obj, ok := result.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if err := fn(obj); err != nil {
+				return pages, err
+			}
+		}
+		next := collectionNextPath(page.Next)
+		if next == path {
+			log.Printf("[ERROR] ApiCollectionIterate next page of %v points to itself\n", path)
+			break
+		}
+		path = next
+	}
+	return pages, nil
+}
+
+// ApiCollectionGetAll returns every object of the collection at path.
+func ApiCollectionGetAll(meta interface{}, path string) ([]map[string]interface{}, error) {
+	var objs []map[string]interface{}
+	err := ApiCollectionIterate(meta, path, func(obj map[string]interface{}) error {
+		objs = append(objs, obj)
+		return nil
+	})
+	return objs, err
+}
+
+// ApiGetObjectByName returns the only object of objType with the given name.
+// When cloudUUID is set the lookup is restricted to that cloud. Like
+// session.GetObject it fails when no object or more than one object matches.
+func ApiGetObjectByName(meta interface{}, objType string, name string, cloudUUID string) (interface{}, error) {
+	path := "api/" + objType + "?name=" + url.QueryEscape(name)
+	if cloudUUID != "" {
+		path = path + "&cloud_ref.uuid=" + url.QueryEscape(cloudUUID)
+	}
+	path = path + "&skip_default=true"
+	objs, err := ApiCollectionGetAll(meta, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(objs) == 0 {
+		return nil, errors.New("No object of type " + objType + " with name " + name + " is found")
+	} else if len(objs) > 1 {
+		return nil, errors.New("More than one object of type " + objType + " with name " + name + " is found")
+	}
+	return objs[0], nil
+}
+
+// collectionPagePath adds the page_size query parameter to path unless it is
+// already present.
+func collectionPagePath(path string, pageSize int) string {
+	if pageSize <= 0 || strings.Contains(path, "page_size=") {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "page_size=" + strconv.Itoa(pageSize)
+}
+
+// collectionNextPath converts the absolute next link of a collection page into
+// a path relative to the controller as expected by the session.
+func collectionNextPath(next string) string {
+	if next == "" {
+		return ""
+	}
+	if i := strings.Index(next, "/api/"); i >= 0 {
+		return next[i+1:]
+	}
+	return strings.TrimPrefix(next, "/")
+}
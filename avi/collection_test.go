@@ -0,0 +1,229 @@
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/avinetworks/sdk/go/clients"
+	"github.com/avinetworks/sdk/go/session"
+)
+
+// newTestAviClient starts a fake controller that accepts the session login
+// and passes every other request to handler.
+func newTestAviClient(t *testing.T, handler http.HandlerFunc) (*clients.AviClient, *httptest.Server) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.WriteHeader(http.StatusOK)
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: "csrf"})
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: "session"})
+			fmt.Fprint(w, "{}")
+		default:
+			handler(w, r)
+		}
+	}))
+	client, err := clients.NewAviClient(strings.TrimPrefix(server.URL, "https://"), "admin",
+		session.SetPassword("admin"), session.SetInsecure)
+	if err != nil {
+		server.Close()
+		t.Fatalf("err: %s", err)
+	}
+	return client, server
+}
+
+// testProviderSettings registers the default settings for client with the
+// given page size and returns them.
+func testProviderSettings(client *clients.AviClient, pageSize int) *providerSettings {
+	settings := newProviderSettings(client)
+	settings.pageSize = pageSize
+	setProviderSettings(client, settings)
+	return settings
+}
+
+// testCollectionHandler serves objs as a paginated collection. The next links
+// are absolute urls as returned by the controller.
+func testCollectionHandler(t *testing.T, objs []map[string]interface{}, requests *int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		*requests++
+		q := r.URL.Query()
+		pageSize, _ := strconv.Atoi(q.Get("page_size"))
+		if pageSize == 0 {
+			pageSize = 25
+		}
+		page, _ := strconv.Atoi(q.Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		matched := []map[string]interface{}{}
+		for _, obj := range objs {
+			if name := q.Get("name"); name != "" && obj["name"] != name {
+				continue
+			}
+			matched = append(matched, obj)
+		}
+		start := (page - 1) * pageSize
+		end := start + pageSize
+		if end > len(matched) {
+			end = len(matched)
+		}
+		resp := map[string]interface{}{
+			"count":   len(matched),
+			"results": matched[start:end],
+		}
+		if end < len(matched) {
+			q.Set("page", strconv.Itoa(page+1))
+			resp["next"] = "https://" + r.Host + r.URL.Path + "?" + q.Encode()
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Errorf("err: %s", err)
+		}
+	}
+}
+
+// testJSONHandler serves the JSON body of routes for the request path and
+// 404 for any other path.
+func testJSONHandler(routes map[string]string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, ok := routes[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "not found"}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	}
+}
+
+func testPools(count int) []map[string]interface{} {
+	var objs []map[string]interface{}
+	for i := 0; i < count; i++ {
+		uuid := fmt.Sprintf("pool-%d", i)
+		objs = append(objs, map[string]interface{}{
+			"uuid": uuid,
+			"url":  "https://localhost/api/pool/" + uuid,
+			"name": fmt.Sprintf("pool%d", i),
+		})
+	}
+	return objs
+}
+
+func TestApiCollectionIterateMultiplePages(t *testing.T) {
+	requests := 0
+	client, server := newTestAviClient(t, testCollectionHandler(t, testPools(7), &requests))
+	defer server.Close()
+	testProviderSettings(client, 3)
+
+	var uuids []string
+	err := ApiCollectionIterate(client, "api/pool?skip_default=true", func(obj map[string]interface{}) error {
+		uuids = append(uuids, obj["uuid"].(string))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(uuids) != 7 {
+		t.Fatalf("expected 7 objects, got %d: %v", len(uuids), uuids)
+	}
+	for i, uuid := range uuids {
+		if uuid != fmt.Sprintf("pool-%d", i) {
+			t.Fatalf("unexpected object %v at index %d", uuid, i)
+		}
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 page requests, got %d", requests)
+	}
+}
+
+func TestApiCollectionIterateStopsOnError(t *testing.T) {
+	requests := 0
+	client, server := newTestAviClient(t, testCollectionHandler(t, testPools(7), &requests))
+	defer server.Close()
+	testProviderSettings(client, 3)
+
+	seen := 0
+	err := ApiCollectionIterate(client, "api/pool", func(obj map[string]interface{}) error {
+		seen++
+		if seen == 4 {
+			return fmt.Errorf("stop")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "stop" {
+		t.Fatalf("expected stop error, got %v", err)
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 page requests, got %d", requests)
+	}
+}
+
+func TestApiGetObjectByName(t *testing.T) {
+	requests := 0
+	pools := testPools(5)
+	pools = append(pools, map[string]interface{}{"uuid": "pool-dup", "name": "pool1"})
+	client, server := newTestAviClient(t, testCollectionHandler(t, pools, &requests))
+	defer server.Close()
+	testProviderSettings(client, 1)
+
+	obj, err := ApiGetObjectByName(client, "pool", "pool3", "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if uuid := obj.(map[string]interface{})["uuid"]; uuid != "pool-3" {
+		t.Fatalf("expected pool-3, got %v", uuid)
+	}
+	if _, err := ApiGetObjectByName(client, "pool", "pool1", ""); err == nil {
+		t.Fatalf("expected an error for a name matched on two pages")
+	}
+	if _, err := ApiGetObjectByName(client, "pool", "missing", ""); err == nil {
+		t.Fatalf("expected an error for a missing name")
+	}
+}
+
+func TestResourceImporterMultiplePages(t *testing.T) {
+	requests := 0
+	client, server := newTestAviClient(t, testCollectionHandler(t, testPools(5), &requests))
+	defer server.Close()
+	testProviderSettings(client, 2)
+
+	d := resourceAviPool().TestResourceData()
+	results, err := ResourceImporter(d, client, "pool", ResourcePoolSchema())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("expected 5 imported pools, got %d", len(results))
+	}
+	if id := results[4].Id(); id != "https://localhost/api/pool/pool-4" {
+		t.Fatalf("unexpected id %v", id)
+	}
+}
+
+func TestCollectionPaths(t *testing.T) {
+	cases := []struct {
+		path     string
+		pageSize int
+		expected string
+	}{
+		{"api/pool", 50, "api/pool?page_size=50"},
+		{"api/pool?name=a", 50, "api/pool?name=a&page_size=50"},
+		{"api/pool?page_size=10", 50, "api/pool?page_size=10"},
+		{"api/pool", 0, "api/pool"},
+	}
+	for _, c := range cases {
+		if p := collectionPagePath(c.path, c.pageSize); p != c.expected {
+			t.Errorf("collectionPagePath(%v, %v) = %v, expected %v", c.path, c.pageSize, p, c.expected)
+		}
+	}
+	if p := collectionNextPath("https://10.10.10.10/api/pool?page=2&page_size=50"); p != "api/pool?page=2&page_size=50" {
+		t.Errorf("unexpected next path %v", p)
+	}
+	if p := collectionNextPath(""); p != "" {
+		t.Errorf("unexpected next path %v", p)
+	}
+}
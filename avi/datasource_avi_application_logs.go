@@ -0,0 +1,267 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// logsPollInterval and logsPollAttempts bound the polling of a logs query
+// that the controller is still collecting from the service engines.
+var (
+	logsPollInterval = time.Second
+	logsPollAttempts = 30
+)
+
+var responseCodeRangeRe = regexp.MustCompile(`^([1-5][0-9][0-9])(-([1-5][0-9][0-9]))?$`)
+
+// aviLogsResponse is a page of api/analytics/logs.
+type aviLogsResponse struct {
+	Count            int                      `json:"count"`
+	PercentRemaining float64                  `json:"percent_remaining"`
+	Results          []map[string]interface{} `json:"results"`
+}
+
+func dataSourceAviApplicationLogs() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviApplicationLogsRead,
+		Schema: map[string]*schema.Schema{
+			"virtualservice_ref": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"log_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "application",
+				ValidateFunc: validateLogType,
+			},
+			"duration": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  300,
+			},
+			"response_code_ranges": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateResponseCodeRange,
+				},
+			},
+			"client_ip": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"uri": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"significant_only": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  100,
+			},
+			"num_logs": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"counts": {
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+			"logs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"timestamp": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"log_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"client_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"method": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uri_path": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"response_code": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"significance": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"raw": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviApplicationLogsRead(d *schema.ResourceData, meta interface{}) error {
+	_, uuid, err := entityRefUUID(meta, "virtualservice_ref", d.Get("virtualservice_ref").(string),
+		[]string{"virtualservice"})
+	if err != nil {
+		return err
+	}
+	logType := "1"
+	if d.Get("log_type").(string) == "connection" {
+		logType = "0"
+	}
+	path := "api/analytics/logs?type=" + logType + "&virtualservice=" + url.QueryEscape(uuid) +
+		"&duration=" + strconv.Itoa(d.Get("duration").(int)) + "&page_size=" + strconv.Itoa(d.Get("limit").(int))
+	if !d.Get("significant_only").(bool) {
+		path += "&udf=true&nf=true"
+	}
+	if clientIP, ok := d.GetOk("client_ip"); ok {
+		path += "&filter=" + url.QueryEscape(fmt.Sprintf("eq(client_ip,%v)", clientIP))
+	}
+	if uri, ok := d.GetOk("uri"); ok {
+		path += "&filter=" + url.QueryEscape(fmt.Sprintf("co(uri_path,%q)", uri))
+	}
+
+	// the controller ANDs filters, so each response code range is a query.
+	var ranges []string
+	seen := make(map[string]bool)
+	for _, r := range d.Get("response_code_ranges").([]interface{}) {
+		if !seen[r.(string)] {
+			seen[r.(string)] = true
+			ranges = append(ranges, r.(string))
+		}
+	}
+	count := 0
+	counts := make(map[string]interface{})
+	var logs []interface{}
+	query := func(path string) (int, error) {
+		resp, err := getLogs(meta, path)
+		if err != nil {
+			return 0, err
+		}
+		for _, record := range resp.Results {
+			logs = append(logs, logRecord(record))
+		}
+		count += resp.Count
+		return resp.Count, nil
+	}
+	if len(ranges) == 0 {
+		if _, err := query(path); err != nil {
+			return err
+		}
+	}
+	for _, r := range ranges {
+		n, err := query(path + responseCodeFilter(r))
+		if err != nil {
+			return err
+		}
+		counts[r] = strconv.Itoa(n)
+	}
+
+	d.SetId(path + "#" + strings.Join(ranges, ","))
+	d.Set("num_logs", count)
+	d.Set("counts", counts)
+	if err := d.Set("logs", logs); err != nil {
+		log.Printf("[ERROR] dataSourceAviApplicationLogsRead %v in setting logs\n", err)
+		return err
+	}
+	return nil
+}
+
+// getLogs runs a logs query until the controller has collected the logs
+// from all service engines.
+func getLogs(meta interface{}, path string) (*aviLogsResponse, error) {
+	for attempt := 1; ; attempt++ {
+		var resp aviLogsResponse
+		if err := aviSession(meta).Get(path, &resp); err != nil {
+			log.Printf("[ERROR] getLogs %v in GET of %v\n", err, path)
+			return nil, err
+		}
+		if resp.PercentRemaining <= 0 {
+			return &resp, nil
+		}
+		if attempt >= logsPollAttempts {
+			return nil, fmt.Errorf("logs query %v is still %v%% incomplete after %v attempts",
+				path, resp.PercentRemaining, attempt)
+		}
+		log.Printf("[DEBUG] getLogs %v%% of %v remaining\n", resp.PercentRemaining, path)
+		time.Sleep(logsPollInterval)
+	}
+}
+
+// responseCodeFilter returns the filter parameters of a range such as 500-599
+// or of a single code such as 404.
+func responseCodeFilter(r string) string {
+	m := responseCodeRangeRe.FindStringSubmatch(r)
+	if m[3] == "" {
+		return "&filter=" + url.QueryEscape(fmt.Sprintf("eq(response_code,%v)", m[1]))
+	}
+	return "&filter=" + url.QueryEscape(fmt.Sprintf("ge(response_code,%v)", m[1])) +
+		"&filter=" + url.QueryEscape(fmt.Sprintf("le(response_code,%v)", m[3]))
+}
+
+func logRecord(record map[string]interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	if raw, err := json.Marshal(record); err == nil {
+		m["raw"] = string(raw)
+	}
+	for key, attr := range map[string]string{"report_timestamp": "timestamp", "client_ip": "client_ip",
+		"method": "method", "uri_path": "uri_path", "significance": "significance"} {
+		if v, ok := record[key].(string); ok {
+			m[attr] = v
+		}
+	}
+	for key, attr := range map[string]string{"log_id": "log_id", "response_code": "response_code"} {
+		if v, ok := record[key].(float64); ok {
+			m[attr] = int(v)
+		}
+	}
+	return m
+}
+
+func validateLogType(v interface{}, k string) (ws []string, es []error) {
+	if t := v.(string); t != "application" && t != "connection" {
+		es = append(es, fmt.Errorf("%q must be application or connection, got %q", k, t))
+	}
+	return
+}
+
+func validateResponseCodeRange(v interface{}, k string) (ws []string, es []error) {
+	m := responseCodeRangeRe.FindStringSubmatch(v.(string))
+	if m == nil {
+		es = append(es, fmt.Errorf("%q: invalid response code range %q, expected a code such as 404 or a range such as 500-599", k, v))
+	} else if m[3] != "" && m[3] < m[1] {
+		es = append(es, fmt.Errorf("%q: invalid response code range %q, the end is before the start", k, v))
+	}
+	return
+}
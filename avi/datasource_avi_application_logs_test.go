@@ -0,0 +1,97 @@
+package avi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDataSourceAviApplicationLogsRead(t *testing.T) {
+	defer func(interval time.Duration, attempts int) {
+		logsPollInterval, logsPollAttempts = interval, attempts
+	}(logsPollInterval, logsPollAttempts)
+	logsPollInterval = 0
+	var queries []string
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/analytics/logs" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		q := r.URL.Query()
+		queries = append(queries, strings.Join(q["filter"], " "))
+		if q.Get("type") != "1" || q.Get("virtualservice") != "vs-1" || q.Get("duration") != "300" ||
+			q.Get("nf") != "true" {
+			t.Errorf("unexpected query %v", r.URL.RawQuery)
+		}
+		// the first request for 5xx is still being collected.
+		if len(queries) == 1 {
+			fmt.Fprint(w, `{"count": 0, "percent_remaining": 50.0, "results": []}`)
+			return
+		}
+		if strings.Contains(queries[len(queries)-1], "500") {
+			fmt.Fprint(w, `{"count": 1, "percent_remaining": 0, "results": [{"log_id": 7,
+				"report_timestamp": "2017-07-01T10:00:00", "client_ip": "10.1.1.1", "method": "GET",
+				"uri_path": "/login", "response_code": 503, "significance": "Server error"}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"count": 0, "results": []}`)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviApplicationLogs().TestResourceData()
+	d.Set("virtualservice_ref", "https://localhost/api/virtualservice/vs-1#web")
+	d.Set("log_type", "application")
+	d.Set("duration", 300)
+	d.Set("limit", 100)
+	d.Set("client_ip", "10.1.1.1")
+	d.Set("response_code_ranges", []string{"500-599", "404", "500-599"})
+	if err := dataSourceAviApplicationLogsRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expectedQueries := []string{
+		"eq(client_ip,10.1.1.1) ge(response_code,500) le(response_code,599)",
+		"eq(client_ip,10.1.1.1) ge(response_code,500) le(response_code,599)",
+		"eq(client_ip,10.1.1.1) eq(response_code,404)",
+	}
+	if fmt.Sprint(queries) != fmt.Sprint(expectedQueries) {
+		t.Errorf("queries = %q, expected %q", queries, expectedQueries)
+	}
+	expected := map[string]interface{}{
+		"num_logs":             1,
+		"counts.500-599":       "1",
+		"counts.404":           "0",
+		"logs.#":               1,
+		"logs.0.log_id":        7,
+		"logs.0.response_code": 503,
+		"logs.0.uri_path":      "/login",
+		"logs.0.timestamp":     "2017-07-01T10:00:00",
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+
+	logsPollAttempts = 1
+	queries = nil
+	d.Set("response_code_ranges", []string{"500-599"})
+	if err := dataSourceAviApplicationLogsRead(d, client); err == nil || !strings.Contains(err.Error(), "incomplete") {
+		t.Errorf("expected an incomplete query error, got %v", err)
+	}
+}
+
+func TestValidateResponseCodeRange(t *testing.T) {
+	for _, r := range []string{"404", "500-599", "200-200"} {
+		if _, es := validateResponseCodeRange(r, "response_code_ranges"); len(es) != 0 {
+			t.Errorf("%v: unexpected errors %v", r, es)
+		}
+	}
+	for _, r := range []string{"5xx", "600", "599-500", "500-"} {
+		if _, es := validateResponseCodeRange(r, "response_code_ranges"); len(es) == 0 {
+			t.Errorf("%v: expected an error", r)
+		}
+	}
+}
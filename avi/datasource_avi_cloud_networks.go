@@ -0,0 +1,292 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+func dataSourceAviCloudNetworks() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviCloudNetworksRead,
+		Schema: map[string]*schema.Schema{
+			"cloud_ref": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
+			"cidr": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCIDROrIP,
+			},
+			"vrf_context_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"uuids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"urls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"networks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vrf_context_ref": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"dhcp_enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"subnets": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"cidr": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"configured": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"total_ip_count": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"used_ip_count": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"free_ip_count": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviCloudNetworksRead(d *schema.ResourceData, meta interface{}) error {
+	cloudUUID, err := refFilterUUID(meta, "cloud_ref", d.Get("cloud_ref").(string), "cloud")
+	if err != nil {
+		return err
+	}
+	path := "api/network?include_name=true&cloud_ref.uuid=" + url.QueryEscape(cloudUUID)
+	if ref, ok := d.GetOk("vrf_context_ref"); ok {
+		vrfUUID, err := refFilterUUID(meta, "vrf_context_ref", ref.(string), "vrfcontext")
+		if err != nil {
+			return err
+		}
+		path += "&vrf_context_ref.uuid=" + url.QueryEscape(vrfUUID)
+	}
+	var nameRe *regexp.Regexp
+	if nameRegex, ok := d.GetOk("name_regex"); ok {
+		nameRe = regexp.MustCompile(nameRegex.(string))
+	}
+	var networks []*models.Network
+	err = ApiCollectionIterate(meta, path, func(obj map[string]interface{}) error {
+		if name, _ := obj["name"].(string); nameRe != nil && !nameRe.MatchString(name) {
+			return nil
+		}
+		var network models.Network
+		if err := jsonToModel(obj, &network); err != nil {
+			return err
+		}
+		networks = append(networks, &network)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] dataSourceAviCloudNetworksRead %v in listing %v\n", err, path)
+		return err
+	}
+
+	// discovered subnets and the ip usage are in the network runtime.
+	runtimes := make(map[string]*models.NetworkRuntime)
+	if len(networks) > 0 {
+		err = ApiCollectionIterate(meta, "api/networkruntime", func(obj map[string]interface{}) error {
+			var runtime models.NetworkRuntime
+			if err := jsonToModel(obj, &runtime); err != nil {
+				return err
+			}
+			if runtime.UUID != nil {
+				runtimes[*runtime.UUID] = &runtime
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("[ERROR] dataSourceAviCloudNetworksRead %v in listing api/networkruntime\n", err)
+			return err
+		}
+	}
+
+	var filter *net.IPNet
+	if cidr, ok := d.GetOk("cidr"); ok {
+		filter = parseCIDROrIP(cidr.(string))
+	}
+	uuids := []string{}
+	names := []string{}
+	urls := []string{}
+	var result []interface{}
+	for _, network := range networks {
+		if network.UUID == nil {
+			continue
+		}
+		subnets := networkSubnets(network, runtimes[*network.UUID])
+		if filter != nil && !subnetsContain(subnets, filter) {
+			continue
+		}
+		m := map[string]interface{}{"uuid": *network.UUID, "subnets": subnets}
+		for k, v := range map[string]*string{"name": network.Name, "url": network.URL,
+			"vrf_context_ref": network.VrfContextRef} {
+			if v != nil {
+				m[k] = strings.SplitN(*v, "#", 2)[0]
+			}
+		}
+		m["dhcp_enabled"] = network.DhcpEnabled != nil && *network.DhcpEnabled
+		uuids = append(uuids, *network.UUID)
+		name, _ := m["name"].(string)
+		names = append(names, name)
+		networkURL, _ := m["url"].(string)
+		urls = append(urls, networkURL)
+		result = append(result, m)
+	}
+
+	d.SetId(fmt.Sprintf("%v#%v#%v", path, d.Get("name_regex"), d.Get("cidr")))
+	d.Set("uuids", uuids)
+	d.Set("names", names)
+	d.Set("urls", urls)
+	if err := d.Set("networks", result); err != nil {
+		log.Printf("[ERROR] dataSourceAviCloudNetworksRead %v in setting networks\n", err)
+		return err
+	}
+	return nil
+}
+
+// networkSubnets returns the configured subnets of network followed by the
+// subnets only discovered by the cloud, with the ip usage of the runtime.
+func networkSubnets(network *models.Network, runtime *models.NetworkRuntime) []interface{} {
+	var subnets []interface{}
+	byCIDR := make(map[string]map[string]interface{})
+	for _, subnet := range network.ConfiguredSubnets {
+		if cidr := prefixCIDR(subnet.Prefix); cidr != "" && byCIDR[cidr] == nil {
+			byCIDR[cidr] = map[string]interface{}{"cidr": cidr, "configured": true}
+			subnets = append(subnets, byCIDR[cidr])
+		}
+	}
+	if runtime == nil {
+		return subnets
+	}
+	for _, subnet := range runtime.SubnetRuntime {
+		cidr := prefixCIDR(subnet.Prefix)
+		if cidr == "" {
+			continue
+		}
+		m := byCIDR[cidr]
+		if m == nil {
+			m = map[string]interface{}{"cidr": cidr, "configured": false}
+			byCIDR[cidr] = m
+			subnets = append(subnets, m)
+		}
+		for k, v := range map[string]*int32{"total_ip_count": subnet.TotalIPCount,
+			"used_ip_count": subnet.UsedIPCount, "free_ip_count": subnet.FreeIPCount} {
+			if v != nil {
+				m[k] = int(*v)
+			}
+		}
+	}
+	return subnets
+}
+
+func prefixCIDR(prefix *models.IPAddrPrefix) string {
+	if prefix == nil || prefix.IPAddr == nil || prefix.IPAddr.Addr == nil || prefix.Mask == nil {
+		return ""
+	}
+	return *prefix.IPAddr.Addr + "/" + strconv.Itoa(int(*prefix.Mask))
+}
+
+// subnetsContain returns whether one of subnets contains the network or
+// address of filter.
+func subnetsContain(subnets []interface{}, filter *net.IPNet) bool {
+	filterOnes, filterBits := filter.Mask.Size()
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.(map[string]interface{})["cidr"].(string))
+		if err != nil {
+			continue
+		}
+		ones, bits := ipNet.Mask.Size()
+		if bits == filterBits && ones <= filterOnes && ipNet.Contains(filter.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseCIDROrIP parses a network such as 10.10.0.0/16 or a single address.
+func parseCIDROrIP(s string) *net.IPNet {
+	if _, ipNet, err := net.ParseCIDR(s); err == nil {
+		return ipNet
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+func validateCIDROrIP(v interface{}, k string) (ws []string, es []error) {
+	if parseCIDROrIP(v.(string)) == nil {
+		es = append(es, fmt.Errorf("%q: %q is not a network such as 10.10.0.0/16 or an ip address", k, v))
+	}
+	return
+}
@@ -0,0 +1,72 @@
+package avi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceAviCloudNetworksRead(t *testing.T) {
+	var listQuery string
+	routes := testJSONHandler(map[string]string{
+		"/api/cloud": `{"count": 1, "results": [{"uuid": "cloud-1", "name": "Default-Cloud"}]}`,
+		"/api/network": `{"count": 2, "results": [
+			{"uuid": "net-1", "name": "vip-net", "url": "https://localhost/api/network/net-1#vip-net",
+				"vrf_context_ref": "https://localhost/api/vrfcontext/vrf-1#global",
+				"configured_subnets": [{"prefix": {"ip_addr": {"addr": "10.10.1.0", "type": "V4"}, "mask": 24}}]},
+			{"uuid": "net-2", "name": "mgmt-net", "url": "https://localhost/api/network/net-2#mgmt-net",
+				"dhcp_enabled": true}]}`,
+		"/api/networkruntime": `{"count": 2, "results": [
+			{"uuid": "net-1", "name": "vip-net", "subnet_runtime": [
+				{"prefix": {"ip_addr": {"addr": "10.10.1.0", "type": "V4"}, "mask": 24},
+					"total_ip_count": 254, "used_ip_count": 4, "free_ip_count": 250}]},
+			{"uuid": "net-2", "name": "mgmt-net", "subnet_runtime": [
+				{"prefix": {"ip_addr": {"addr": "10.20.0.0", "type": "V4"}, "mask": 16}}]}]}`,
+	})
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/network" {
+			listQuery = r.URL.RawQuery
+		}
+		routes(w, r)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviCloudNetworks().TestResourceData()
+	d.Set("cloud_ref", "/api/cloud/?name=Default-Cloud")
+	if err := dataSourceAviCloudNetworksRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := "include_name=true&cloud_ref.uuid=cloud-1"; listQuery != expected {
+		t.Errorf("query = %v, expected %v", listQuery, expected)
+	}
+	expected := map[string]interface{}{
+		"uuids.#":                            2,
+		"urls.0":                             "https://localhost/api/network/net-1",
+		"networks.0.vrf_context_ref":         "https://localhost/api/vrfcontext/vrf-1",
+		"networks.0.subnets.#":               1,
+		"networks.0.subnets.0.cidr":          "10.10.1.0/24",
+		"networks.0.subnets.0.configured":    true,
+		"networks.0.subnets.0.free_ip_count": 250,
+		"networks.1.dhcp_enabled":            true,
+		"networks.1.subnets.0.cidr":          "10.20.0.0/16",
+		"networks.1.subnets.0.configured":    false,
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+
+	for cidr, uuid := range map[string]string{"10.20.5.0/24": "net-2", "10.10.1.7": "net-1", "10.0.0.0/8": ""} {
+		d := dataSourceAviCloudNetworks().TestResourceData()
+		d.Set("cloud_ref", "cloud-1")
+		d.Set("cidr", cidr)
+		if err := dataSourceAviCloudNetworksRead(d, client); err != nil {
+			t.Fatalf("%v: err: %s", cidr, err)
+		}
+		uuids := d.Get("uuids").([]interface{})
+		if uuid == "" && len(uuids) != 0 || uuid != "" && (len(uuids) != 1 || uuids[0] != uuid) {
+			t.Errorf("%v: uuids = %v, expected %v", cidr, uuids, uuid)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+// aviCloudStatus is the part of api/cloud/<uuid>/status that
+// avi_cloud_runtime exports.
+type aviCloudStatus struct {
+	State           string            `json:"state"`
+	Reason          string            `json:"reason"`
+	LastChangedTime *models.TimeStamp `json:"last_changed_time,omitempty"`
+}
+
+// ready returns whether service engines can be placed in the cloud.
+func (s *aviCloudStatus) ready() bool {
+	return s.State == "CLOUD_STATE_PLACEMENT_READY"
+}
+
+// changedSince returns whether s was reported after before, that is whether
+// its state or its last change time differ.
+func (s *aviCloudStatus) changedSince(before *aviCloudStatus) bool {
+	if before == nil || s.State != before.State {
+		return true
+	}
+	return timeStampAfter(s.LastChangedTime, before.LastChangedTime)
+}
+
+// timeStampAfter returns whether t is later than before. A missing time is
+// never later.
+func timeStampAfter(t, before *models.TimeStamp) bool {
+	if t == nil || t.Secs == nil {
+		return false
+	}
+	if before == nil || before.Secs == nil {
+		return true
+	}
+	var usecs, beforeUsecs int64
+	if t.Usecs != nil {
+		usecs = *t.Usecs
+	}
+	if before.Usecs != nil {
+		beforeUsecs = *before.Usecs
+	}
+	return *t.Secs > *before.Secs || *t.Secs == *before.Secs && usecs > beforeUsecs
+}
+
+func dataSourceAviCloudRuntime() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviCloudRuntimeRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"uuid": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"tenant_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"vtype": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"reason": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ready": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"network_sync_complete": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAviCloudRuntimeRead(d *schema.ResourceData, meta interface{}) error {
+	sess := aviSession(meta)
+	uuid, err := dataSourceObjectUUID(d, meta, "cloud")
+	if err != nil {
+		return err
+	}
+	var cloud models.Cloud
+	path := "api/cloud/" + uuid
+	if err := sess.Get(path, &cloud); err != nil {
+		log.Printf("[ERROR] dataSourceAviCloudRuntimeRead %v in GET of %v\n", err, path)
+		return err
+	}
+	status, err := getCloudStatus(meta, uuid)
+	if err != nil {
+		return err
+	}
+	var runtime models.CloudRuntime
+	path = "api/cloudruntime/" + uuid
+	if err := sess.Get(path, &runtime); err != nil {
+		log.Printf("[ERROR] dataSourceAviCloudRuntimeRead %v in GET of %v\n", err, path)
+		return err
+	}
+
+	d.SetId(uuid)
+	d.Set("uuid", uuid)
+	for k, v := range map[string]*string{"name": cloud.Name, "tenant_ref": cloud.TenantRef, "vtype": cloud.Vtype} {
+		if v != nil {
+			d.Set(k, *v)
+		}
+	}
+	d.Set("state", status.State)
+	d.Set("reason", status.Reason)
+	d.Set("ready", status.ready())
+	d.Set("network_sync_complete", runtime.NetworkSyncComplete != nil && *runtime.NetworkSyncComplete)
+	return nil
+}
+
+func getCloudStatus(meta interface{}, uuid string) (*aviCloudStatus, error) {
+	var status aviCloudStatus
+	path := "api/cloud/" + uuid + "/status"
+	if err := aviSession(meta).Get(path, &status); err != nil {
+		log.Printf("[ERROR] getCloudStatus %v in GET of %v\n", err, path)
+		return nil, err
+	}
+	return &status, nil
+}
@@ -0,0 +1,34 @@
+package avi
+
+import (
+	"testing"
+)
+
+func TestDataSourceAviCloudRuntimeRead(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/cloud":                `{"count": 1, "results": [{"uuid": "cloud-1", "name": "Default-Cloud"}]}`,
+		"/api/cloud/cloud-1":        `{"uuid": "cloud-1", "name": "Default-Cloud", "vtype": "CLOUD_VCENTER"}`,
+		"/api/cloud/cloud-1/status": `{"state": "CLOUD_STATE_PLACEMENT_READY"}`,
+		"/api/cloudruntime/cloud-1": `{"uuid": "cloud-1", "name": "Default-Cloud", "network_sync_complete": true}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviCloudRuntime().TestResourceData()
+	d.Set("name", "Default-Cloud")
+	if err := dataSourceAviCloudRuntimeRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"uuid":                  "cloud-1",
+		"vtype":                 "CLOUD_VCENTER",
+		"state":                 "CLOUD_STATE_PLACEMENT_READY",
+		"ready":                 true,
+		"network_sync_complete": true,
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strings"
+)
+
+// aviClusterRuntime is the part of api/cluster/runtime that
+// avi_cluster_runtime exports.
+type aviClusterRuntime struct {
+	ClusterState struct {
+		State    string `json:"state"`
+		Reason   string `json:"reason"`
+		Progress int    `json:"progress"`
+		UpSince  string `json:"up_since"`
+	} `json:"cluster_state"`
+	NodeStates []struct {
+		Name    string `json:"name"`
+		MgmtIP  string `json:"mgmt_ip"`
+		Role    string `json:"role"`
+		State   string `json:"state"`
+		UpSince string `json:"up_since"`
+	} `json:"node_states"`
+	ServiceStates []struct {
+		ServiceName string `json:"service_name"`
+		NodeName    string `json:"node_name"`
+		Role        string `json:"role"`
+		State       string `json:"state"`
+	} `json:"service_states"`
+}
+
+// ready returns whether the cluster is up, with or without high
+// availability.
+func (r *aviClusterRuntime) ready() bool {
+	return strings.HasPrefix(r.ClusterState.State, "CLUSTER_UP")
+}
+
+// aviInitialData is the part of api/initial-data that has the controller
+// version.
+type aviInitialData struct {
+	Version struct {
+		Version string `json:"Version"`
+		Build   int    `json:"build"`
+	} `json:"version"`
+}
+
+func dataSourceAviClusterRuntime() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviClusterRuntimeRead,
+		Schema: map[string]*schema.Schema{
+			"state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"reason": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"progress": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"up_since": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ready": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"leader": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"leader_ip": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"version": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"build": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"nodes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mgmt_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"role": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"up_since": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"services": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"role": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"state": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviClusterRuntimeRead(d *schema.ResourceData, meta interface{}) error {
+	runtime, err := getClusterRuntime(meta)
+	if err != nil {
+		return err
+	}
+	var initialData aviInitialData
+	if err := aviSession(meta).Get("api/initial-data", &initialData); err != nil {
+		log.Printf("[ERROR] dataSourceAviClusterRuntimeRead %v in GET of api/initial-data\n", err)
+		return err
+	}
+
+	services := make(map[string][]interface{})
+	for _, service := range runtime.ServiceStates {
+		services[service.NodeName] = append(services[service.NodeName], map[string]interface{}{
+			"name":  service.ServiceName,
+			"role":  service.Role,
+			"state": service.State,
+		})
+	}
+	var leader, leaderIP string
+	var nodes []interface{}
+	for _, node := range runtime.NodeStates {
+		if node.Role == "CLUSTER_LEADER" {
+			leader, leaderIP = node.Name, node.MgmtIP
+		}
+		nodes = append(nodes, map[string]interface{}{
+			"name":     node.Name,
+			"mgmt_ip":  node.MgmtIP,
+			"role":     node.Role,
+			"state":    node.State,
+			"up_since": node.UpSince,
+			"services": services[node.Name],
+		})
+	}
+
+	d.SetId("cluster-runtime")
+	d.Set("state", runtime.ClusterState.State)
+	d.Set("reason", runtime.ClusterState.Reason)
+	d.Set("progress", runtime.ClusterState.Progress)
+	d.Set("up_since", runtime.ClusterState.UpSince)
+	d.Set("ready", runtime.ready())
+	d.Set("leader", leader)
+	d.Set("leader_ip", leaderIP)
+	d.Set("version", initialData.Version.Version)
+	d.Set("build", initialData.Version.Build)
+	if err := d.Set("nodes", nodes); err != nil {
+		log.Printf("[ERROR] dataSourceAviClusterRuntimeRead %v in setting nodes\n", err)
+		return err
+	}
+	return nil
+}
+
+func getClusterRuntime(meta interface{}) (*aviClusterRuntime, error) {
+	var runtime aviClusterRuntime
+	if err := aviSession(meta).Get("api/cluster/runtime", &runtime); err != nil {
+		log.Printf("[ERROR] getClusterRuntime %v in GET of api/cluster/runtime\n", err)
+		return nil, err
+	}
+	return &runtime, nil
+}
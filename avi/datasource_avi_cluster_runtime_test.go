@@ -0,0 +1,45 @@
+package avi
+
+import (
+	"testing"
+)
+
+func TestDataSourceAviClusterRuntimeRead(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/cluster/runtime": `{"cluster_state": {"state": "CLUSTER_UP_HA_ACTIVE", "progress": 100,
+				"up_since": "2017-07-01 10:00:00"},
+			"node_states": [
+				{"name": "10.10.1.1", "mgmt_ip": "10.10.1.1", "role": "CLUSTER_FOLLOWER", "state": "CLUSTER_ACTIVE"},
+				{"name": "10.10.1.2", "mgmt_ip": "10.10.1.2", "role": "CLUSTER_LEADER", "state": "CLUSTER_ACTIVE"}],
+			"service_states": [
+				{"service_name": "apiserver", "node_name": "10.10.1.2", "role": "CLUSTER_LEADER", "state": "CLUSTER_ACTIVE"},
+				{"service_name": "apiserver", "node_name": "10.10.1.1", "role": "CLUSTER_FOLLOWER", "state": "CLUSTER_STARTING"}]}`,
+		"/api/initial-data": `{"version": {"Version": "17.2.1", "build": 9012}}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviClusterRuntime().TestResourceData()
+	if err := dataSourceAviClusterRuntimeRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"state":                    "CLUSTER_UP_HA_ACTIVE",
+		"progress":                 100,
+		"ready":                    true,
+		"leader":                   "10.10.1.2",
+		"leader_ip":                "10.10.1.2",
+		"version":                  "17.2.1",
+		"build":                    9012,
+		"nodes.#":                  2,
+		"nodes.0.role":             "CLUSTER_FOLLOWER",
+		"nodes.0.services.0.state": "CLUSTER_STARTING",
+		"nodes.1.services.#":       1,
+		"nodes.1.services.0.name":  "apiserver",
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+}
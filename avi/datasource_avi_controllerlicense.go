@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+)
+
+func licenseLimitsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"cores": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"burst_cores": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"sockets": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"max_ses": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"se_bandwidth_limits": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"count": {
+						Type:     schema.TypeInt,
+						Computed: true,
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceAviControllerLicense reads the licenses installed on the
+// controller and the license capacity the service engines consume.
+func dataSourceAviControllerLicense() *schema.Resource {
+	s := licenseLimitsSchema()
+	for k, v := range map[string]*schema.Schema{
+		"customer_name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"start_on": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"valid_until": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"license_tiers": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: withLicenseLimits(map[string]*schema.Schema{
+					"tier_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				}),
+			},
+		},
+		"licenses": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: withLicenseLimits(map[string]*schema.Schema{
+					"license_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"license_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"license_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"tier_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"start_on": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"valid_until": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				}),
+			},
+		},
+		"used_cores": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"used_sockets": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"used_ses": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"remaining_cores": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+	} {
+		s[k] = v
+	}
+	return &schema.Resource{
+		Read:   dataSourceAviControllerLicenseRead,
+		Schema: s,
+	}
+}
+
+func withLicenseLimits(s map[string]*schema.Schema) map[string]*schema.Schema {
+	for k, v := range licenseLimitsSchema() {
+		s[k] = v
+	}
+	return s
+}
+
+func dataSourceAviControllerLicenseRead(d *schema.ResourceData, meta interface{}) error {
+	license, err := getControllerLicense(meta)
+	if err != nil {
+		return err
+	}
+	// the license is consumed by the vcpus or sockets of the service engines.
+	usedCores, usedSockets, usedSes := 0, 0, 0
+	err = ApiCollectionIterate(meta, "api/serviceengine", func(obj map[string]interface{}) error {
+		usedSes++
+		if resources, ok := obj["resources"].(map[string]interface{}); ok {
+			vcpus, _ := resources["num_vcpus"].(float64)
+			sockets, _ := resources["sockets"].(float64)
+			usedCores += int(vcpus)
+			usedSockets += int(sockets)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] dataSourceAviControllerLicenseRead %v in listing service engines\n", err)
+		return err
+	}
+
+	limits := licenseLimits(license.Cores, license.BurstCores, license.Sockets, license.MaxSes,
+		license.SeBandwidthLimits)
+	for k, v := range limits {
+		if err := d.Set(k, v); err != nil {
+			return err
+		}
+	}
+	var tiers []interface{}
+	for _, tier := range license.LicenseTiers {
+		m := licenseLimits(tier.Cores, tier.BurstCores, tier.Sockets, tier.MaxSes, tier.SeBandwidthLimits)
+		setStringValue(m, "tier_type", tier.TierType)
+		tiers = append(tiers, m)
+	}
+	var licenses []interface{}
+	for _, l := range license.Licenses {
+		licenses = append(licenses, singleLicenseAttributes(l))
+	}
+
+	d.SetId("controllerlicense")
+	for k, v := range map[string]*string{"customer_name": license.CustomerName, "start_on": license.StartOn,
+		"valid_until": license.ValidUntil} {
+		if v != nil {
+			d.Set(k, *v)
+		}
+	}
+	if err := d.Set("license_tiers", tiers); err != nil {
+		return err
+	}
+	if err := d.Set("licenses", licenses); err != nil {
+		log.Printf("[ERROR] dataSourceAviControllerLicenseRead %v in setting licenses\n", err)
+		return err
+	}
+	d.Set("used_cores", usedCores)
+	d.Set("used_sockets", usedSockets)
+	d.Set("used_ses", usedSes)
+	d.Set("remaining_cores", limits["cores"].(int)-usedCores)
+	return nil
+}
+
+func getControllerLicense(meta interface{}) (*models.ControllerLicense, error) {
+	var license models.ControllerLicense
+	path := "api/license"
+	if err := aviSession(meta).Get(path, &license); err != nil {
+		log.Printf("[ERROR] getControllerLicense %v in GET of path %v\n", err, path)
+		return nil, err
+	}
+	return &license, nil
+}
+
+// singleLicenseAttributes returns the attributes of an installed license.
+func singleLicenseAttributes(l *models.SingleLicense) map[string]interface{} {
+	m := licenseLimits(l.Cores, l.BurstCores, l.Sockets, l.MaxSes, l.SeBandwidthLimits)
+	for k, v := range map[string]*string{"license_id": l.LicenseID, "license_name": l.LicenseName,
+		"license_type": l.LicenseType, "tier_type": l.TierType, "start_on": l.StartOn,
+		"valid_until": l.ValidUntil} {
+		setStringValue(m, k, v)
+	}
+	return m
+}
+
+// licenseLimits returns the attributes of licenseLimitsSchema.
+func licenseLimits(cores, burstCores, sockets, maxSes *int32, bandwidth []*models.SEBandwidthLimit) map[string]interface{} {
+	m := make(map[string]interface{})
+	for k, v := range map[string]*int32{"cores": cores, "burst_cores": burstCores, "sockets": sockets,
+		"max_ses": maxSes} {
+		m[k] = 0
+		if v != nil {
+			m[k] = int(*v)
+		}
+	}
+	var limits []interface{}
+	for _, limit := range bandwidth {
+		if limit == nil {
+			continue
+		}
+		l := make(map[string]interface{})
+		setStringValue(l, "type", limit.Type)
+		if limit.Count != nil {
+			l["count"] = int(*limit.Count)
+		}
+		limits = append(limits, l)
+	}
+	m["se_bandwidth_limits"] = limits
+	return m
+}
+
+func setStringValue(m map[string]interface{}, k string, v *string) {
+	if v != nil {
+		m[k] = *v
+	}
+}
@@ -0,0 +1,48 @@
+package avi
+
+import (
+	"testing"
+)
+
+func TestDataSourceAviControllerLicenseRead(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/license": `{"customer_name": "Example", "valid_until": "2018-07-01 00:00:00", "cores": 20,
+			"max_ses": 10, "license_tiers": [{"tier_type": "ENTERPRISE_18", "cores": 20}],
+			"licenses": [
+				{"license_id": "lic-1", "license_name": "Example Enterprise", "license_type": "LIC_CORES",
+					"tier_type": "ENTERPRISE_18", "cores": 16, "valid_until": "2018-07-01 00:00:00"},
+				{"license_id": "lic-2", "license_name": "Example Bandwidth", "license_type": "LIC_SE_BANDWIDTH",
+					"se_bandwidth_limits": [{"type": "SE_BANDWIDTH_200M", "count": 4}]}]}`,
+		"/api/serviceengine": `{"count": 2, "results": [
+			{"uuid": "se-1", "resources": {"num_vcpus": 2, "sockets": 1}},
+			{"uuid": "se-2", "resources": {"num_vcpus": 4, "sockets": 1}}]}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviControllerLicense().TestResourceData()
+	if err := dataSourceAviControllerLicenseRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"customer_name":                          "Example",
+		"cores":                                  20,
+		"max_ses":                                10,
+		"license_tiers.0.tier_type":              "ENTERPRISE_18",
+		"licenses.#":                             2,
+		"licenses.0.license_id":                  "lic-1",
+		"licenses.0.cores":                       16,
+		"licenses.0.valid_until":                 "2018-07-01 00:00:00",
+		"licenses.1.se_bandwidth_limits.0.type":  "SE_BANDWIDTH_200M",
+		"licenses.1.se_bandwidth_limits.0.count": 4,
+		"used_cores":                             6,
+		"used_sockets":                           2,
+		"used_ses":                               2,
+		"remaining_cores":                        14,
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// metricsEntityTypes are the object types avi_metrics reads metrics of.
+var metricsEntityTypes = []string{"virtualservice", "pool", "serviceengine"}
+
+func dataSourceAviMetrics() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviMetricsRead,
+		Schema: map[string]*schema.Schema{
+			"entity_ref": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"metric_ids": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"step": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  300,
+			},
+			"limit": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  12,
+			},
+			"series": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"metric_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"units": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"timestamps": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"values": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeFloat},
+						},
+						"avg": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"max": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"min": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviMetricsRead(d *schema.ResourceData, meta interface{}) error {
+	objType, uuid, err := entityRefUUID(meta, "entity_ref", d.Get("entity_ref").(string), metricsEntityTypes)
+	if err != nil {
+		return err
+	}
+	var metricIDs []string
+	for _, id := range d.Get("metric_ids").([]interface{}) {
+		metricIDs = append(metricIDs, id.(string))
+	}
+	path := metricsPath(objType, uuid, metricIDs, d.Get("step").(int), d.Get("limit").(int))
+	var resp models.MetricsQueryResponse
+	if err := aviSession(meta).Get(path, &resp); err != nil {
+		log.Printf("[ERROR] dataSourceAviMetricsRead %v in GET of %v\n", err, path)
+		return err
+	}
+
+	// series are exported in the order of metric_ids.
+	order := make(map[string]int)
+	for i, id := range metricIDs {
+		order[id] = i
+	}
+	sort.SliceStable(resp.Series, func(i, j int) bool {
+		return metricsSeriesOrder(resp.Series[i], order) < metricsSeriesOrder(resp.Series[j], order)
+	})
+	var series []interface{}
+	for _, s := range resp.Series {
+		if s == nil || s.Header == nil || s.Header.Name == nil {
+			continue
+		}
+		name := *s.Header.Name
+		m := map[string]interface{}{"metric_id": name}
+		if s.Header.Units != nil {
+			m["units"] = *s.Header.Units
+		}
+		timestamps := []string{}
+		values := []float64{}
+		for _, point := range s.Data {
+			if point == nil || point.Value == nil || (point.IsNull != nil && *point.IsNull) {
+				continue
+			}
+			if point.Timestamp != nil {
+				timestamps = append(timestamps, *point.Timestamp)
+			} else {
+				timestamps = append(timestamps, "")
+			}
+			values = append(values, *point.Value)
+		}
+		m["timestamps"] = timestamps
+		m["values"] = values
+		if len(values) > 0 {
+			avg, max, min := metricsSummary(values)
+			m["avg"], m["max"], m["min"] = avg, max, min
+		}
+		series = append(series, m)
+	}
+
+	d.SetId(path)
+	if err := d.Set("series", series); err != nil {
+		log.Printf("[ERROR] dataSourceAviMetricsRead %v in setting series\n", err)
+		return err
+	}
+	return nil
+}
+
+// metricsPath returns the path of the last limit samples, step seconds apart,
+// of metricIDs of an object.
+func metricsPath(objType string, uuid string, metricIDs []string, step int, limit int) string {
+	return "api/analytics/metrics/" + objType + "/" + uuid + "?metric_id=" +
+		url.QueryEscape(strings.Join(metricIDs, ",")) + "&step=" + strconv.Itoa(step) + "&limit=" + strconv.Itoa(limit)
+}
+
+func metricsSeriesOrder(s *models.MetricsDataSeries, order map[string]int) int {
+	if s != nil && s.Header != nil && s.Header.Name != nil {
+		if i, ok := order[*s.Header.Name]; ok {
+			return i
+		}
+	}
+	return len(order)
+}
+
+// metricsSummary returns the average, maximum and minimum of values, which
+// must not be empty.
+func metricsSummary(values []float64) (avg float64, max float64, min float64) {
+	max, min = values[0], values[0]
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+		if v > max {
+			max = v
+		}
+		if v < min {
+			min = v
+		}
+	}
+	return sum / float64(len(values)), max, min
+}
@@ -0,0 +1,66 @@
+package avi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceAviMetricsRead(t *testing.T) {
+	var query string
+	routes := testJSONHandler(map[string]string{
+		"/api/pool": `{"count": 1, "results": [{"uuid": "pool-1", "name": "web"}]}`,
+		"/api/analytics/metrics/pool/pool-1": `{"series": [
+			{"header": {"name": "l4_server.avg_errored_connections"}, "data": []},
+			{"header": {"name": "l4_server.avg_bandwidth", "units": "BITS_PER_SECOND"}, "data": [
+				{"timestamp": "2017-07-01T10:00:00", "value": 10},
+				{"timestamp": "2017-07-01T10:05:00", "is_null": true, "value": 0},
+				{"timestamp": "2017-07-01T10:10:00", "value": 30}]}]}`,
+	})
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/analytics/metrics/pool/pool-1" {
+			query = r.URL.RawQuery
+		}
+		routes(w, r)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	for _, ref := range []string{"https://localhost/api/pool/pool-1#web", "/api/pool/?name=web"} {
+		d := dataSourceAviMetrics().TestResourceData()
+		d.Set("entity_ref", ref)
+		d.Set("metric_ids", []string{"l4_server.avg_bandwidth", "l4_server.avg_errored_connections"})
+		d.Set("step", 300)
+		d.Set("limit", 12)
+		if err := dataSourceAviMetricsRead(d, client); err != nil {
+			t.Fatalf("%v: err: %s", ref, err)
+		}
+		if expected := "metric_id=l4_server.avg_bandwidth%2Cl4_server.avg_errored_connections&step=300&limit=12"; query != expected {
+			t.Errorf("query = %v, expected %v", query, expected)
+		}
+		expected := map[string]interface{}{
+			"series.#":              2,
+			"series.0.metric_id":    "l4_server.avg_bandwidth",
+			"series.0.units":        "BITS_PER_SECOND",
+			"series.0.values.#":     2,
+			"series.0.values.1":     30.0,
+			"series.0.timestamps.1": "2017-07-01T10:10:00",
+			"series.0.avg":          20.0,
+			"series.0.min":          10.0,
+			"series.0.max":          30.0,
+			"series.1.metric_id":    "l4_server.avg_errored_connections",
+			"series.1.values.#":     0,
+		}
+		for k, v := range expected {
+			if actual := d.Get(k); actual != v {
+				t.Errorf("%v: %v = %v, expected %v", ref, k, actual, v)
+			}
+		}
+	}
+
+	d := dataSourceAviMetrics().TestResourceData()
+	d.Set("entity_ref", "https://localhost/api/cloud/cloud-1")
+	d.Set("metric_ids", []string{"l4_server.avg_bandwidth"})
+	if err := dataSourceAviMetricsRead(d, client); err == nil {
+		t.Fatalf("expected an error for a cloud entity_ref")
+	}
+}
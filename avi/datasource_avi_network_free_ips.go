@@ -0,0 +1,294 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/apparentlymart/go-cidr/cidr"
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// ipReservationPrefix starts the names of the string groups that
+// avi_network_ip_reservation creates to reserve addresses of a network.
+// Object names are unique, so two runs cannot reserve the same address.
+const ipReservationPrefix = "terraform-ip-reservation-"
+
+func ipReservationName(networkUUID string, ip string) string {
+	return ipReservationPrefix + networkUUID + "-" + ip
+}
+
+func dataSourceAviNetworkFreeIPs() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviNetworkFreeIPsRead,
+		Schema: map[string]*schema.Schema{
+			"network_ref": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"num_ips": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      1,
+				ValidateFunc: validateNumIPs,
+			},
+			"subnet": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateCIDROrIP,
+			},
+			"ip_addresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceAviNetworkFreeIPsRead(d *schema.ResourceData, meta interface{}) error {
+	networkUUID, err := refFilterUUID(meta, "network_ref", d.Get("network_ref").(string), "network")
+	if err != nil {
+		return err
+	}
+	var subnet *net.IPNet
+	if s, ok := d.GetOk("subnet"); ok {
+		subnet = parseCIDROrIP(s.(string))
+	}
+	count := d.Get("num_ips").(int)
+	ips, err := networkFreeIPs(meta, networkUUID, subnet, count)
+	if err != nil {
+		return err
+	}
+	if len(ips) < count {
+		return fmt.Errorf("only %v of %v addresses are free in the static ranges of network %v",
+			len(ips), count, networkUUID)
+	}
+	d.SetId(networkUUID + "#" + d.Get("subnet").(string) + "#" + strconv.Itoa(count))
+	d.Set("ip_addresses", ips)
+	return nil
+}
+
+func validateNumIPs(v interface{}, k string) (ws []string, es []error) {
+	if n := v.(int); n < 1 {
+		es = append(es, fmt.Errorf("%q must be at least 1, got %d", k, n))
+	}
+	return
+}
+
+// networkFreeIPs returns up to count addresses of the static ranges of a
+// network, within subnet if it is not nil, that no vip, service engine, pool
+// server or reservation uses.
+func networkFreeIPs(meta interface{}, networkUUID string, subnet *net.IPNet, count int) ([]string, error) {
+	var network models.Network
+	path := "api/network/" + networkUUID
+	if err := aviSession(meta).Get(path, &network); err != nil {
+		log.Printf("[ERROR] networkFreeIPs %v in GET of %v\n", err, path)
+		return nil, err
+	}
+	used, err := networkUsedIPs(meta, networkUUID)
+	if err != nil {
+		return nil, err
+	}
+	ips := []string{}
+	add := func(ip net.IP) bool {
+		if subnet != nil && !subnet.Contains(ip) {
+			return true
+		}
+		if !used[ip.String()] {
+			ips = append(ips, ip.String())
+			used[ip.String()] = true
+		}
+		return len(ips) < count
+	}
+	for _, s := range network.ConfiguredSubnets {
+		if s == nil {
+			continue
+		}
+		for _, r := range s.StaticRanges {
+			begin, end := modelIP(r.Begin), modelIP(r.End)
+			if begin == nil || end == nil {
+				continue
+			}
+			// only the part of the range in subnet is searched.
+			if subnet != nil {
+				first, last := cidr.AddressRange(subnet)
+				begin, end = maxIP(begin, first), minIP(end, last)
+			}
+			for ip := begin; ip != nil && bytes.Compare(ip, end) <= 0; ip = nextIP(ip) {
+				if !add(ip) {
+					return ips, nil
+				}
+			}
+		}
+		for _, staticIP := range s.StaticIps {
+			if ip := modelIP(staticIP); ip != nil && !add(ip) {
+				return ips, nil
+			}
+		}
+	}
+	return ips, nil
+}
+
+// networkUsedIPs returns the addresses used by vips, service engines and pool
+// servers, allocated by the network runtime or reserved in the network. The
+// objects of every tenant are listed, as a network of the admin tenant may be
+// used by all of them.
+func networkUsedIPs(meta interface{}, networkUUID string) (map[string]bool, error) {
+	used := make(map[string]bool)
+	addAddr := func(addr *aviIPAddr) {
+		if addr != nil && addr.Addr != "" {
+			used[normalizeIP(addr.Addr)] = true
+		}
+	}
+	addVips := func(obj map[string]interface{}) error {
+		var o struct {
+			Vip []aviVip `json:"vip"`
+		}
+		if err := jsonToModel(obj, &o); err != nil {
+			return err
+		}
+		for _, vip := range o.Vip {
+			for _, addr := range []*aviIPAddr{vip.IPAddress, vip.IP6Address, vip.FloatingIP, vip.FloatingIP6} {
+				addAddr(addr)
+			}
+		}
+		return nil
+	}
+	collections := []struct {
+		path string
+		fn   func(obj map[string]interface{}) error
+	}{
+		{"api/vsvip", addVips},
+		{"api/virtualservice", addVips},
+		{"api/serviceengine", func(obj map[string]interface{}) error {
+			var se models.ServiceEngine
+			if err := jsonToModel(obj, &se); err != nil {
+				return err
+			}
+			for _, vnic := range append([]*models.VNIC{se.MgmtVnic}, se.DataVnics...) {
+				for _, ip := range vnicIPs(vnic) {
+					used[normalizeIP(ip)] = true
+				}
+			}
+			return nil
+		}},
+		{"api/pool", func(obj map[string]interface{}) error {
+			var pool struct {
+				Servers []struct {
+					IP *aviIPAddr `json:"ip"`
+				} `json:"servers"`
+			}
+			if err := jsonToModel(obj, &pool); err != nil {
+				return err
+			}
+			for _, server := range pool.Servers {
+				addAddr(server.IP)
+			}
+			return nil
+		}},
+		{"api/stringgroup?name.contains=" + url.QueryEscape(ipReservationName(networkUUID, "")),
+			func(obj map[string]interface{}) error {
+				name, _ := obj["name"].(string)
+				if ip := strings.TrimPrefix(name, ipReservationName(networkUUID, "")); ip != name {
+					used[normalizeIP(ip)] = true
+				}
+				return nil
+			}},
+	}
+	allTenants := withTenant(meta, "*")
+	for _, c := range collections {
+		if err := ApiCollectionIterate(allTenants, c.path, c.fn); err != nil {
+			log.Printf("[ERROR] networkUsedIPs %v in listing %v\n", err, c.path)
+			return nil, err
+		}
+	}
+
+	var runtime models.NetworkRuntime
+	path := "api/networkruntime/" + networkUUID
+	if err := aviSession(meta).Get(path, &runtime); err != nil {
+		// a network without runtime has no allocated addresses.
+		if !strings.Contains(err.Error(), "404") {
+			log.Printf("[ERROR] networkUsedIPs %v in GET of %v\n", err, path)
+			return nil, err
+		}
+	}
+	for _, s := range runtime.SubnetRuntime {
+		if s == nil {
+			continue
+		}
+		for _, alloc := range s.IPAlloced {
+			if alloc != nil {
+				if ip := modelIP(alloc.IP); ip != nil {
+					used[ip.String()] = true
+				}
+			}
+		}
+	}
+	return used, nil
+}
+
+func modelIP(addr *models.IPAddr) net.IP {
+	if addr == nil || addr.Addr == nil {
+		return nil
+	}
+	ip := net.ParseIP(*addr.Addr)
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4
+	}
+	return ip
+}
+
+// normalizeIP returns the canonical form of an address, so that addresses
+// written differently compare equal.
+func normalizeIP(addr string) string {
+	if ip := net.ParseIP(addr); ip != nil {
+		return ip.String()
+	}
+	return addr
+}
+
+// nextIP returns the address after ip, or nil after the last address.
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			return next
+		}
+	}
+	return nil
+}
+
+func sameFamily(a net.IP, b net.IP) (net.IP, net.IP) {
+	if a4, b4 := a.To4(), b.To4(); a4 != nil && b4 != nil {
+		return a4, b4
+	}
+	return a.To16(), b.To16()
+}
+
+func maxIP(a net.IP, b net.IP) net.IP {
+	a, b = sameFamily(a, b)
+	if bytes.Compare(a, b) < 0 {
+		return b
+	}
+	return a
+}
+
+func minIP(a net.IP, b net.IP) net.IP {
+	a, b = sameFamily(a, b)
+	if bytes.Compare(a, b) > 0 {
+		return b
+	}
+	return a
+}
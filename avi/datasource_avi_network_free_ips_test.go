@@ -0,0 +1,98 @@
+package avi
+
+import (
+	"github.com/avinetworks/sdk/go/models"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+// testNetworkRoutes serves a network with a static range of 10.10.1.10 to
+// 10.10.1.20 and the static address 10.10.1.30, of which 10.10.1.10 to
+// 10.10.1.14 are in use.
+func testNetworkRoutes() map[string]string {
+	return map[string]string{
+		"/api/network/net-1": `{"uuid": "net-1", "name": "vip-net", "configured_subnets": [
+			{"prefix": {"ip_addr": {"addr": "10.10.1.0", "type": "V4"}, "mask": 24},
+				"static_ranges": [{"begin": {"addr": "10.10.1.10", "type": "V4"}, "end": {"addr": "10.10.1.20", "type": "V4"}}],
+				"static_ips": [{"addr": "10.10.1.30", "type": "V4"}]}]}`,
+		"/api/vsvip": `{"count": 1, "results": [{"uuid": "vsvip-1",
+			"vip": [{"vip_id": "0", "ip_address": {"addr": "10.10.1.10", "type": "V4"}}]}]}`,
+		"/api/virtualservice": `{"count": 0, "results": []}`,
+		"/api/serviceengine": `{"count": 1, "results": [{"uuid": "se-1", "data_vnics": [
+			{"vnic_networks": [{"ip": {"ip_addr": {"addr": "10.10.1.11", "type": "V4"}, "mask": 24}}]}]}]}`,
+		"/api/pool": `{"count": 1, "results": [{"uuid": "pool-1",
+			"servers": [{"ip": {"addr": "10.10.1.12", "type": "V4"}}]}]}`,
+		"/api/networkruntime/net-1": `{"uuid": "net-1", "subnet_runtime": [
+			{"prefix": {"ip_addr": {"addr": "10.10.1.0", "type": "V4"}, "mask": 24},
+				"ip_alloced": [{"ip": {"addr": "10.10.1.13", "type": "V4"}, "se_uuid": "se-1"}]}]}`,
+		"/api/stringgroup": `{"count": 1, "results": [{"uuid": "stringgroup-1",
+			"name": "terraform-ip-reservation-net-1-10.10.1.14"}]}`,
+	}
+}
+
+func TestDataSourceAviNetworkFreeIPsRead(t *testing.T) {
+	routes := testJSONHandler(testNetworkRoutes())
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// used addresses are looked up in every tenant.
+		if r.URL.Path != "/api/network/net-1" && r.URL.Path != "/api/networkruntime/net-1" &&
+			r.Header.Get("X-Avi-Tenant") != "*" {
+			t.Errorf("%v listed in tenant %q", r.URL.Path, r.Header.Get("X-Avi-Tenant"))
+		}
+		routes(w, r)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	for _, c := range []struct {
+		subnet   string
+		numIPs   int
+		expected []interface{}
+	}{
+		{"", 3, []interface{}{"10.10.1.15", "10.10.1.16", "10.10.1.17"}},
+		{"", 7, []interface{}{"10.10.1.15", "10.10.1.16", "10.10.1.17", "10.10.1.18",
+			"10.10.1.19", "10.10.1.20", "10.10.1.30"}},
+		{"10.10.1.18/31", 2, []interface{}{"10.10.1.18", "10.10.1.19"}},
+		{"10.10.1.18/31", 3, nil},
+	} {
+		d := dataSourceAviNetworkFreeIPs().TestResourceData()
+		d.Set("network_ref", "net-1")
+		d.Set("num_ips", c.numIPs)
+		d.Set("subnet", c.subnet)
+		err := dataSourceAviNetworkFreeIPsRead(d, client)
+		if c.expected == nil {
+			if err == nil {
+				t.Errorf("%v/%v: expected an error", c.subnet, c.numIPs)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v/%v: err: %s", c.subnet, c.numIPs, err)
+		}
+		if actual := d.Get("ip_addresses").([]interface{}); !reflect.DeepEqual(actual, c.expected) {
+			t.Errorf("%v/%v: ip_addresses = %v, expected %v", c.subnet, c.numIPs, actual, c.expected)
+		}
+	}
+}
+
+func TestValidateNumIPs(t *testing.T) {
+	for n, valid := range map[int]bool{1: true, 16: true, 0: false, -1: false} {
+		if _, es := validateNumIPs(n, "num_ips"); (len(es) == 0) != valid {
+			t.Errorf("validateNumIPs(%v) = %v, expected valid %v", n, es, valid)
+		}
+	}
+}
+
+func TestNextIP(t *testing.T) {
+	for ip, expected := range map[string]string{
+		"10.0.0.255":      "10.0.1.0",
+		"10.0.0.1":        "10.0.0.2",
+		"2001:db8::ffff":  "2001:db8::1:0",
+		"255.255.255.255": "<nil>",
+	} {
+		addr := modelIP(&models.IPAddr{Addr: &ip})
+		if actual := nextIP(addr).String(); actual != expected {
+			t.Errorf("nextIP(%v) = %v, expected %v", ip, actual, expected)
+		}
+	}
+}
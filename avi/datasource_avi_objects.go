@@ -0,0 +1,239 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// addListDataSources adds a plural data source, such as avi_pools for
+// avi_pool, for every object data source except the given ones. The plural of
+// a type that already ends in s, such as avi_wafcrs, is avi_wafcrs_list.
+func addListDataSources(dataSources map[string]*schema.Resource, except ...string) {
+	skip := make(map[string]bool)
+	for _, name := range except {
+		skip[name] = true
+	}
+	singular := make([]string, 0, len(dataSources))
+	for name := range dataSources {
+		singular = append(singular, name)
+	}
+	for _, name := range singular {
+		objType := strings.TrimPrefix(name, "avi_")
+		if skip[name] || IsPostNotAllowed(objType) {
+			continue
+		}
+		dataSources[listDataSourceName(name)] = dataSourceAviObjects(objType, dataSources[name].Schema)
+	}
+}
+
+// listDataSourceName returns the name of the plural data source of name.
+func listDataSourceName(name string) string {
+	if strings.HasSuffix(name, "s") {
+		return name + "_list"
+	}
+	return name + "s"
+}
+
+// dataSourceAviObjects returns the data source listing the objects of objType
+// that match its filters. Each object is exported with the attributes of the
+// singular data source, whose schema is s.
+func dataSourceAviObjects(objType string, s map[string]*schema.Schema) *schema.Resource {
+	return &schema.Resource{
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return dataSourceAviObjectsRead(d, meta, objType, s)
+		},
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
+			"tenant": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cloud_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"filters": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"uuids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"urls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"objects": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Resource{Schema: computedSchema(s)},
+			},
+		},
+	}
+}
+
+func dataSourceAviObjectsRead(d *schema.ResourceData, meta interface{}, objType string,
+	s map[string]*schema.Schema) error {
+	path := objectsPath(objType, d.Get("cloud_ref").(string), d.Get("filters").(map[string]interface{}))
+	var nameRe *regexp.Regexp
+	if nameRegex, ok := d.GetOk("name_regex"); ok {
+		nameRe = regexp.MustCompile(nameRegex.(string))
+	}
+	tenant := d.Get("tenant").(string)
+	uuids := []string{}
+	names := []string{}
+	urls := []string{}
+	objects := []interface{}{}
+	// the objects of another tenant are only listed in that tenant, which
+	// also lists the objects shared with it by the admin tenant.
+	err := ApiCollectionIterate(withTenant(meta, tenant), path, func(obj map[string]interface{}) error {
+		name, _ := obj["name"].(string)
+		if nameRe != nil && !nameRe.MatchString(name) {
+			return nil
+		}
+		if tenant != "" && !refMatchesName(obj["tenant_ref"], tenant) {
+			return nil
+		}
+		obj = stripObjectRefNames(obj).(map[string]interface{})
+		uuid, _ := obj["uuid"].(string)
+		objURL, _ := obj["url"].(string)
+		uuids = append(uuids, uuid)
+		names = append(names, name)
+		urls = append(urls, objURL)
+		m, err := ApiDataToSchema(obj, map[string]interface{}{}, s)
+		if err != nil {
+			return err
+		}
+		objects = append(objects, m)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] dataSourceAviObjectsRead %v in listing %v\n", err, path)
+		return err
+	}
+	d.SetId(fmt.Sprintf("%v#%v#%v", path, d.Get("name_regex"), tenant))
+	d.Set("uuids", uuids)
+	d.Set("names", names)
+	d.Set("urls", urls)
+	if err := d.Set("objects", objects); err != nil {
+		log.Printf("[ERROR] dataSourceAviObjectsRead %v in setting objects\n", err)
+		return err
+	}
+	return nil
+}
+
+// objectsPath returns the collection path of objType restricted to the cloud
+// of cloudRef and to the field values of filters. References in the objects
+// include the name of the referred object so that they can be matched by
+// name.
+func objectsPath(objType string, cloudRef string, filters map[string]interface{}) string {
+	query := url.Values{}
+	query.Set("include_name", "true")
+	if cloudRef != "" {
+		query.Set("cloud_ref.uuid", UUIDFromID(cloudRef))
+	}
+	for k, v := range filters {
+		query.Set(k, fmt.Sprintf("%v", v))
+	}
+	return "api/" + objType + "?" + query.Encode()
+}
+
+// refMatchesName reports whether ref, a reference that includes the name of
+// the referred object, refers to the object with the given name or uuid.
+func refMatchesName(ref interface{}, name string) bool {
+	r, ok := ref.(string)
+	if !ok {
+		return false
+	}
+	parts := strings.SplitN(r, "#", 2)
+	if len(parts) == 2 && parts[1] == name {
+		return true
+	}
+	return UUIDFromID(r) == name
+}
+
+// stripObjectRefNames returns v, an object read with include_name, with the
+// names removed from its url and references, so that they can be used as
+// references in resources.
+func stripObjectRefNames(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			isRef := k == "url" || strings.HasSuffix(k, "_ref") || strings.HasSuffix(k, "_refs")
+			if ref, ok := elem.(string); ok && isRef {
+				m[k] = strings.SplitN(ref, "#", 2)[0]
+			} else if refs, ok := elem.([]interface{}); ok && isRef {
+				stripped := make([]interface{}, 0, len(refs))
+				for _, ref := range refs {
+					if r, ok := ref.(string); ok {
+						ref = strings.SplitN(r, "#", 2)[0]
+					}
+					stripped = append(stripped, ref)
+				}
+				m[k] = stripped
+			} else {
+				m[k] = stripObjectRefNames(elem)
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, 0, len(v))
+		for _, elem := range v {
+			l = append(l, stripObjectRefNames(elem))
+		}
+		return l
+	}
+	return v
+}
+
+// computedSchema returns a copy of s in which every attribute is computed, as
+// needed to export objects of that schema from a data source.
+func computedSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	c := make(map[string]*schema.Schema, len(s))
+	for k, v := range s {
+		cv := &schema.Schema{
+			Type:     v.Type,
+			Computed: true,
+			Set:      v.Set,
+		}
+		switch elem := v.Elem.(type) {
+		case *schema.Resource:
+			cv.Elem = &schema.Resource{Schema: computedSchema(elem.Schema)}
+		case *schema.Schema:
+			cv.Elem = &schema.Schema{Type: elem.Type}
+		default:
+			cv.Elem = v.Elem
+		}
+		c[k] = cv
+	}
+	return c
+}
+
+func validateRegexp(v interface{}, k string) (ws []string, es []error) {
+	if _, err := regexp.Compile(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%q: %v", k, err))
+	}
+	return
+}
@@ -0,0 +1,84 @@
+package avi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestListDataSourceNames(t *testing.T) {
+	dataSources := Provider().(*schema.Provider).DataSourcesMap
+	for _, name := range []string{"avi_pools", "avi_virtualservices", "avi_healthmonitors", "avi_wafcrs_list"} {
+		if _, ok := dataSources[name]; !ok {
+			t.Errorf("missing data source %v", name)
+		}
+	}
+	for _, name := range []string{"avi_systemconfigurations", "avi_servers", "avi_rests"} {
+		if _, ok := dataSources[name]; ok {
+			t.Errorf("unexpected data source %v", name)
+		}
+	}
+}
+
+func TestDataSourceAviObjectsRead(t *testing.T) {
+	requests := 0
+	pools := testPools(5)
+	for i, pool := range pools {
+		pool["tenant_ref"] = "https://localhost/api/tenant/tenant-1#t1"
+		if i == 3 {
+			pool["tenant_ref"] = "https://localhost/api/tenant/tenant-2#t2"
+		}
+		pool["default_server_port"] = 80 + i
+		pool["url"] = pool["url"].(string) + "#" + pool["name"].(string)
+		pool["health_monitor_refs"] = []interface{}{"https://localhost/api/healthmonitor/hm-1#System-HTTP"}
+	}
+	handler := testCollectionHandler(t, pools, &requests)
+	var query string
+	tenants := map[string]bool{}
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if query == "" {
+			query = r.URL.RawQuery
+		}
+		tenants[r.Header.Get("X-Avi-Tenant")] = true
+		handler(w, r)
+	})
+	defer server.Close()
+	testProviderSettings(client, 2)
+
+	d := Provider().(*schema.Provider).DataSourcesMap["avi_pools"].TestResourceData()
+	d.Set("name_regex", "^pool[1-3]$")
+	d.Set("tenant", "t1")
+	d.Set("cloud_ref", "https://localhost/api/cloud/cloud-1")
+	d.Set("filters", map[string]interface{}{"enabled": "true"})
+	if err := dataSourceAviObjectsRead(d, client, "pool", dataSourceAviPool().Schema); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if query != "cloud_ref.uuid=cloud-1&enabled=true&include_name=true&page_size=2" {
+		t.Fatalf("unexpected query %v", query)
+	}
+	uuids := d.Get("uuids").([]interface{})
+	if len(uuids) != 2 || uuids[0] != "pool-1" || uuids[1] != "pool-2" {
+		t.Fatalf("unexpected uuids %v", uuids)
+	}
+	if names := d.Get("names").([]interface{}); names[1] != "pool2" {
+		t.Fatalf("unexpected names %v", names)
+	}
+	if urls := d.Get("urls").([]interface{}); urls[0] != "https://localhost/api/pool/pool-1" {
+		t.Fatalf("unexpected urls %v", urls)
+	}
+	if port := d.Get("objects.1.default_server_port"); port != 82 {
+		t.Fatalf("unexpected default_server_port %v", port)
+	}
+	for k, v := range map[string]interface{}{
+		"objects.0.tenant_ref":            "https://localhost/api/tenant/tenant-1",
+		"objects.0.health_monitor_refs.0": "https://localhost/api/healthmonitor/hm-1",
+	} {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+	if len(tenants) != 1 || !tenants["t1"] {
+		t.Fatalf("pools listed in tenants %v, expected t1", tenants)
+	}
+}
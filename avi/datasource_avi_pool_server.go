@@ -5,11 +5,14 @@
  */
 package avi
 
-import "github.com/hashicorp/terraform/helper/schema"
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+)
 
 func dataSourceAviServer() *schema.Resource {
 	return &schema.Resource{
-		Read: ResourceAviServerRead,
+		Read: dataSourceAviServerRead,
 		Schema: map[string]*schema.Schema{
 			"pool_ref": {
 				Type:     schema.TypeString,
@@ -35,11 +38,21 @@ func dataSourceAviServer() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"discovered_network_ref": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
 			"discovered_networks": {
 				Type:     schema.TypeList,
 				Computed: true,
 				Elem:     ResourceDiscoveredNetworkSchema(),
 			},
+			"discovered_subnet": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     ResourceIpAddrPrefixSchema(),
+			},
 			"enabled": {
 				Type:     schema.TypeBool,
 				Computed: true,
@@ -108,3 +121,15 @@ func dataSourceAviServer() *schema.Resource {
 		},
 	}
 }
+
+// dataSourceAviServerRead reads the server of pool_ref with the given ip and
+// fails when the pool has none.
+func dataSourceAviServerRead(d *schema.ResourceData, meta interface{}) error {
+	if err := ResourceAviServerRead(d, meta); err != nil {
+		return err
+	}
+	if d.Id() == "" {
+		return fmt.Errorf("no server found in pool_ref %q with ip %q", d.Get("pool_ref"), d.Get("ip"))
+	}
+	return nil
+}
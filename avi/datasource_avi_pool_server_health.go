@@ -0,0 +1,237 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strconv"
+)
+
+// aviPoolServerRuntime is an entry of api/pool/<uuid>/runtime/server. The
+// address is in server_ip and server_port as in SCServerStateInfo, or in
+// ip_addr and port as in ServerConfig, depending on the controller version.
+type aviPoolServerRuntime struct {
+	ServerIP   *aviIPAddr    `json:"server_ip"`
+	ServerPort int           `json:"server_port"`
+	IPAddr     *aviIPAddr    `json:"ip_addr"`
+	Port       int           `json:"port"`
+	Hostname   string        `json:"hostname"`
+	OperStatus aviOperStatus `json:"oper_status"`
+}
+
+func (s *aviPoolServerRuntime) key() string {
+	if s.ServerIP != nil {
+		return s.ServerIP.Addr + ":" + strconv.Itoa(s.ServerPort)
+	}
+	if s.IPAddr != nil {
+		return s.IPAddr.Addr + ":" + strconv.Itoa(s.Port)
+	}
+	return ""
+}
+
+func dataSourceAviPoolServerHealth() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviPoolServerHealthRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"uuid": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"cloud_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"tenant_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"num_servers_up": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"servers": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"port": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"oper_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"oper_reasons": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"failure_code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"health_monitors": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"health_monitor": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"response_code": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"response_string": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"average_response_time": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviPoolServerHealthRead(d *schema.ResourceData, meta interface{}) error {
+	sess := aviSession(meta)
+	uuid, err := dataSourceObjectUUID(d, meta, "pool")
+	if err != nil {
+		return err
+	}
+	var pool models.Pool
+	path := "api/pool/" + uuid
+	if err := sess.Get(path, &pool); err != nil {
+		log.Printf("[ERROR] dataSourceAviPoolServerHealthRead %v in GET of %v\n", err, path)
+		return err
+	}
+	var runtimes []aviPoolServerRuntime
+	if err := getRuntimeResults(meta, path+"/runtime/server", &runtimes); err != nil {
+		return err
+	}
+	var hmResults []models.SeHmEventServerDetails
+	if err := getRuntimeResults(meta, path+"/runtime/server/hmonstat", &hmResults); err != nil {
+		return err
+	}
+
+	runtimeByServer := make(map[string]aviPoolServerRuntime)
+	for _, runtime := range runtimes {
+		runtimeByServer[runtime.key()] = runtime
+	}
+	hmByServer := make(map[string]models.SeHmEventServerDetails)
+	for _, hm := range hmResults {
+		if hm.IP != nil && hm.IP.Addr != nil && hm.Port != nil {
+			hmByServer[*hm.IP.Addr+":"+strconv.Itoa(int(*hm.Port))] = hm
+		}
+	}
+
+	var servers []interface{}
+	numUp := 0
+	for _, server := range pool.Servers {
+		if server.IP == nil || server.IP.Addr == nil {
+			continue
+		}
+		// servers without a port use the default port of the pool.
+		port := 0
+		if server.Port != nil {
+			port = int(*server.Port)
+		} else if pool.DefaultServerPort != nil {
+			port = int(*pool.DefaultServerPort)
+		}
+		key := *server.IP.Addr + ":" + strconv.Itoa(port)
+		m := map[string]interface{}{
+			"ip":      *server.IP.Addr,
+			"port":    port,
+			"enabled": server.Enabled == nil || *server.Enabled,
+		}
+		if server.Hostname != nil {
+			m["hostname"] = *server.Hostname
+		}
+		if runtime, ok := runtimeByServer[key]; ok {
+			m["oper_state"] = runtime.OperStatus.State
+			m["oper_reasons"] = runtime.OperStatus.Reason
+			if _, ok := m["hostname"]; !ok && runtime.Hostname != "" {
+				m["hostname"] = runtime.Hostname
+			}
+			if runtime.OperStatus.State == "OPER_UP" {
+				numUp++
+			}
+		}
+		if hm, ok := hmByServer[key]; ok {
+			if hm.FailureCode != nil {
+				m["failure_code"] = *hm.FailureCode
+			}
+			var monitors []interface{}
+			for _, shm := range hm.Shm {
+				monitor := map[string]interface{}{}
+				if shm.HealthMonitor != nil {
+					monitor["health_monitor"] = *shm.HealthMonitor
+				}
+				if shm.ResponseCode != nil {
+					monitor["response_code"] = int(*shm.ResponseCode)
+				}
+				if shm.RespString != nil {
+					monitor["response_string"] = *shm.RespString
+				}
+				if shm.AverageResponseTime != nil {
+					monitor["average_response_time"] = int(*shm.AverageResponseTime)
+				}
+				monitors = append(monitors, monitor)
+			}
+			m["health_monitors"] = monitors
+		}
+		servers = append(servers, m)
+	}
+
+	d.SetId(uuid)
+	d.Set("uuid", uuid)
+	if pool.Name != nil {
+		d.Set("name", *pool.Name)
+	}
+	if pool.CloudRef != nil {
+		d.Set("cloud_ref", *pool.CloudRef)
+	}
+	if pool.TenantRef != nil {
+		d.Set("tenant_ref", *pool.TenantRef)
+	}
+	d.Set("num_servers_up", numUp)
+	if err := d.Set("servers", servers); err != nil {
+		log.Printf("[ERROR] dataSourceAviPoolServerHealthRead %v in setting servers\n", err)
+		return err
+	}
+	return nil
+}
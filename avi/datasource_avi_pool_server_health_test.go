@@ -0,0 +1,49 @@
+package avi
+
+import (
+	"testing"
+)
+
+func TestDataSourceAviPoolServerHealthRead(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/pool/pool-1": `{"uuid": "pool-1", "name": "web", "default_server_port": 8080, "servers": [
+			{"ip": {"addr": "10.0.0.1", "type": "V4"}, "port": 80},
+			{"ip": {"addr": "10.0.0.2", "type": "V4"}, "enabled": false, "hostname": "web2"}]}`,
+		"/api/pool/pool-1/runtime/server": `[
+			{"server_ip": {"addr": "10.0.0.1", "type": "V4"}, "server_port": 80,
+				"oper_status": {"state": "OPER_UP"}},
+			{"server_ip": {"addr": "10.0.0.2", "type": "V4"}, "server_port": 8080,
+				"oper_status": {"state": "OPER_DISABLED", "reason": ["Server disabled"]}}]`,
+		"/api/pool/pool-1/runtime/server/hmonstat": `{"results": [
+			{"ip": {"addr": "10.0.0.2", "type": "V4"}, "port": 8080, "failure_code": "ERR_RESPONSE",
+				"shm": [{"health_monitor": "System-HTTP", "response_code": 503, "resp_string": "busy"}]}]}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviPoolServerHealth().TestResourceData()
+	d.Set("uuid", "pool-1")
+	if err := dataSourceAviPoolServerHealthRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"name":                     "web",
+		"num_servers_up":           1,
+		"servers.#":                2,
+		"servers.0.oper_state":     "OPER_UP",
+		"servers.0.enabled":        true,
+		"servers.1.port":           8080,
+		"servers.1.enabled":        false,
+		"servers.1.hostname":       "web2",
+		"servers.1.oper_reasons.0": "Server disabled",
+		"servers.1.failure_code":   "ERR_RESPONSE",
+		"servers.1.health_monitors.0.health_monitor":  "System-HTTP",
+		"servers.1.health_monitors.0.response_code":   503,
+		"servers.1.health_monitors.0.response_string": "busy",
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/jmespath/go-jmespath"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// dataSourceAviRest reads any GET endpoint of the controller, such as the
+// runtime or status of an object, and exposes the response as raw JSON and
+// as values picked with JMESPath expressions.
+func dataSourceAviRest() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviRestRead,
+		Schema: map[string]*schema.Schema{
+			"path": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"query": {
+				Type:     schema.TypeMap,
+				Optional: true,
+			},
+			"fields": {
+				Type:         schema.TypeMap,
+				Optional:     true,
+				ValidateFunc: validateRestFields,
+			},
+			"response": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"values": {
+				Type:     schema.TypeMap,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceAviRestRead(d *schema.ResourceData, meta interface{}) error {
+	path := restPath(d.Get("path").(string), d.Get("query").(map[string]interface{}))
+	resp, err := aviSession(meta).GetRaw(path)
+	if err != nil {
+		log.Printf("[ERROR] dataSourceAviRestRead %v in GET of path %v\n", err, path)
+		return err
+	}
+	var data interface{}
+	if err := json.Unmarshal(resp, &data); err != nil {
+		return fmt.Errorf("response of %v is not JSON: %v", path, err)
+	}
+	values := make(map[string]interface{})
+	for name, expr := range d.Get("fields").(map[string]interface{}) {
+		result, err := jmespath.Search(expr.(string), data)
+		if err != nil {
+			return fmt.Errorf("field %v: invalid expression %q: %v", name, expr, err)
+		}
+		value, err := restFieldValue(result)
+		if err != nil {
+			return fmt.Errorf("field %v: %v", name, err)
+		}
+		values[name] = value
+	}
+	d.SetId(path)
+	d.Set("response", string(resp))
+	d.Set("values", values)
+	return nil
+}
+
+// restPath returns path relative to the controller with the query parameters
+// added in a stable order.
+func restPath(path string, query map[string]interface{}) string {
+	path = strings.TrimPrefix(path, "/")
+	if !strings.HasPrefix(path, "api/") {
+		path = "api/" + path
+	}
+	if len(query) == 0 {
+		return path
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	params := make([]string, 0, len(keys))
+	for _, k := range keys {
+		params = append(params, url.QueryEscape(k)+"="+url.QueryEscape(fmt.Sprintf("%v", query[k])))
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + strings.Join(params, "&")
+}
+
+// restFieldValue converts the result of a JMESPath expression to a string.
+// Strings are returned as is, no match as an empty string and any other
+// value as its JSON encoding.
+func restFieldValue(result interface{}) (string, error) {
+	switch v := result.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+	value, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func validateRestFields(v interface{}, k string) (ws []string, es []error) {
+	for name, expr := range v.(map[string]interface{}) {
+		if _, err := jmespath.Compile(fmt.Sprintf("%v", expr)); err != nil {
+			es = append(es, fmt.Errorf("%q: invalid expression for %v: %v", k, name, err))
+		}
+	}
+	return
+}
@@ -0,0 +1,52 @@
+package avi
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceAviRestRead(t *testing.T) {
+	var query string
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/virtualservice/vs-1/runtime" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"oper_status": {"state": "OPER_UP"}, "vip_summary": [{"vip_id": "0", "num_se_assigned": 2}]}`)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviRest().TestResourceData()
+	d.Set("path", "/api/virtualservice/vs-1/runtime")
+	d.Set("query", map[string]interface{}{"include_name": "true", "a": "b c"})
+	d.Set("fields", map[string]interface{}{
+		"state":   "oper_status.state",
+		"se":      "vip_summary[0].num_se_assigned",
+		"vips":    "vip_summary[*].vip_id",
+		"missing": "oper_status.reason",
+	})
+	if err := dataSourceAviRestRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if query != "a=b+c&include_name=true" {
+		t.Fatalf("unexpected query %v", query)
+	}
+	expected := map[string]string{"state": "OPER_UP", "se": "2", "vips": `["0"]`, "missing": ""}
+	values := d.Get("values").(map[string]interface{})
+	for k, v := range expected {
+		if values[k] != v {
+			t.Errorf("value %v = %v, expected %v", k, values[k], v)
+		}
+	}
+	if d.Get("response").(string) == "" {
+		t.Fatalf("expected the raw response")
+	}
+
+	d.Set("path", "api/virtualservice/vs-2/runtime")
+	if err := dataSourceAviRestRead(d, client); err == nil {
+		t.Fatalf("expected an error for a missing endpoint")
+	}
+}
@@ -0,0 +1,350 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// seUsageMetrics are the metrics exported as the resource usage of a
+// service engine, by attribute.
+var seUsageMetrics = map[string]string{
+	"se_stats.avg_cpu_usage":   "cpu_usage",
+	"se_stats.avg_mem_usage":   "memory_usage",
+	"se_stats.avg_disk1_usage": "disk_usage",
+}
+
+// aviSeRuntime is the part of api/serviceengine/<uuid>/runtime that
+// avi_serviceengines exports.
+type aviSeRuntime struct {
+	OperStatus  aviOperStatus `json:"oper_status"`
+	Version     string        `json:"version"`
+	SeConnected bool          `json:"se_connected"`
+	PowerState  string        `json:"power_state"`
+	VsRef       []string      `json:"vs_ref"`
+}
+
+func dataSourceAviServiceEngines() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviServiceEnginesRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateRegexp,
+			},
+			"se_group_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cloud_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"uuids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"urls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"mgmt_ips": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"serviceengines": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"uuid": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"se_group_ref": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cloud_ref": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mgmt_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"data_ips": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"oper_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"oper_reasons": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"connected": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"power_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"virtualservice_refs": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"virtualservice_names": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"num_vcpus": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"cpu_usage": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"memory_usage": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"disk_usage": {
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAviServiceEnginesRead(d *schema.ResourceData, meta interface{}) error {
+	path := "api/serviceengine?include_name=true"
+	for _, filter := range []struct{ arg, objType string }{
+		{"se_group_ref", "serviceenginegroup"}, {"cloud_ref", "cloud"}} {
+		ref, ok := d.GetOk(filter.arg)
+		if !ok {
+			continue
+		}
+		uuid, err := refFilterUUID(meta, filter.arg, ref.(string), filter.objType)
+		if err != nil {
+			return err
+		}
+		path += "&" + filter.arg + ".uuid=" + url.QueryEscape(uuid)
+	}
+	var nameRe *regexp.Regexp
+	if nameRegex, ok := d.GetOk("name_regex"); ok {
+		nameRe = regexp.MustCompile(nameRegex.(string))
+	}
+	var ses []*models.ServiceEngine
+	err := ApiCollectionIterate(meta, path, func(obj map[string]interface{}) error {
+		if name, _ := obj["name"].(string); nameRe != nil && !nameRe.MatchString(name) {
+			return nil
+		}
+		var se models.ServiceEngine
+		if err := jsonToModel(obj, &se); err != nil {
+			return err
+		}
+		ses = append(ses, &se)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] dataSourceAviServiceEnginesRead %v in listing %v\n", err, path)
+		return err
+	}
+
+	// the runtime and the metrics of the service engines are fetched
+	// concurrently, bounded by the size of the session pool.
+	inventories := make([]map[string]interface{}, len(ses))
+	errs := make([]error, len(ses))
+	var wg sync.WaitGroup
+	for i, se := range ses {
+		if se.UUID == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, se *models.ServiceEngine) {
+			defer wg.Done()
+			inventories[i], errs[i] = serviceEngineInventory(meta, se)
+		}(i, se)
+	}
+	wg.Wait()
+
+	uuids := []string{}
+	names := []string{}
+	urls := []string{}
+	mgmtIPs := []string{}
+	var serviceEngines []interface{}
+	for i, se := range ses {
+		if se.UUID == nil {
+			continue
+		}
+		if errs[i] != nil {
+			return errs[i]
+		}
+		m := inventories[i]
+		uuids = append(uuids, *se.UUID)
+		name, _ := m["name"].(string)
+		names = append(names, name)
+		seURL, _ := m["url"].(string)
+		urls = append(urls, seURL)
+		// mgmt_ips has one entry per service engine, "" when it has no
+		// management ip.
+		ip, _ := m["mgmt_ip"].(string)
+		mgmtIPs = append(mgmtIPs, ip)
+		serviceEngines = append(serviceEngines, m)
+	}
+
+	d.SetId(fmt.Sprintf("%v#%v", path, d.Get("name_regex")))
+	d.Set("uuids", uuids)
+	d.Set("names", names)
+	d.Set("urls", urls)
+	d.Set("mgmt_ips", mgmtIPs)
+	if err := d.Set("serviceengines", serviceEngines); err != nil {
+		log.Printf("[ERROR] dataSourceAviServiceEnginesRead %v in setting serviceengines\n", err)
+		return err
+	}
+	return nil
+}
+
+// serviceEngineInventory returns the attributes of a service engine from its
+// configuration, its runtime and its latest resource usage metrics.
+func serviceEngineInventory(meta interface{}, se *models.ServiceEngine) (map[string]interface{}, error) {
+	m := map[string]interface{}{"uuid": *se.UUID}
+	for attr, v := range map[string]*string{"name": se.Name, "url": se.URL, "se_group_ref": se.SeGroupRef,
+		"cloud_ref": se.CloudRef} {
+		if v != nil {
+			// references include the name of the referred object.
+			m[attr] = strings.SplitN(*v, "#", 2)[0]
+		}
+	}
+	if ips := vnicIPs(se.MgmtVnic); len(ips) > 0 {
+		m["mgmt_ip"] = ips[0]
+	}
+	dataIPs := []string{}
+	for _, vnic := range se.DataVnics {
+		dataIPs = append(dataIPs, vnicIPs(vnic)...)
+	}
+	m["data_ips"] = dataIPs
+	if se.Resources != nil {
+		for attr, v := range map[string]*int32{"num_vcpus": se.Resources.NumVcpus, "memory": se.Resources.Memory,
+			"disk": se.Resources.Disk} {
+			if v != nil {
+				m[attr] = int(*v)
+			}
+		}
+	}
+
+	var runtime aviSeRuntime
+	path := "api/serviceengine/" + *se.UUID + "/runtime?include_name=true"
+	if err := aviSession(meta).Get(path, &runtime); err != nil {
+		log.Printf("[ERROR] serviceEngineInventory %v in GET of %v\n", err, path)
+		return nil, err
+	}
+	m["oper_state"] = runtime.OperStatus.State
+	m["oper_reasons"] = runtime.OperStatus.Reason
+	m["connected"] = runtime.SeConnected
+	m["power_state"] = runtime.PowerState
+	m["version"] = runtime.Version
+	vsRefs := []string{}
+	vsNames := []string{}
+	for _, ref := range runtime.VsRef {
+		parts := strings.SplitN(ref, "#", 2)
+		vsRefs = append(vsRefs, parts[0])
+		// the names are index aligned with the refs.
+		name := ""
+		if len(parts) == 2 {
+			name = parts[1]
+		}
+		vsNames = append(vsNames, name)
+	}
+	m["virtualservice_refs"] = vsRefs
+	m["virtualservice_names"] = vsNames
+
+	// usage metrics are missing when analytics are not collected, which
+	// leaves the service engine inventory usable.
+	metricIDs := make([]string, 0, len(seUsageMetrics))
+	for id := range seUsageMetrics {
+		metricIDs = append(metricIDs, id)
+	}
+	var metrics models.MetricsQueryResponse
+	path = metricsPath("serviceengine", *se.UUID, metricIDs, 300, 1)
+	if err := aviSession(meta).Get(path, &metrics); err != nil {
+		log.Printf("[WARN] serviceEngineInventory %v in GET of %v\n", err, path)
+		return m, nil
+	}
+	for _, s := range metrics.Series {
+		if s == nil || s.Header == nil || s.Header.Name == nil || seUsageMetrics[*s.Header.Name] == "" {
+			continue
+		}
+		for _, point := range s.Data {
+			if point != nil && point.Value != nil && (point.IsNull == nil || !*point.IsNull) {
+				m[seUsageMetrics[*s.Header.Name]] = *point.Value
+			}
+		}
+	}
+	return m, nil
+}
+
+// vnicIPs returns the ip addresses of the networks of vnic.
+func vnicIPs(vnic *models.VNIC) []string {
+	var ips []string
+	if vnic == nil {
+		return ips
+	}
+	for _, network := range vnic.VnicNetworks {
+		if network != nil && network.IP != nil && network.IP.IPAddr != nil && network.IP.IPAddr.Addr != nil {
+			ips = append(ips, *network.IP.IPAddr.Addr)
+		}
+	}
+	return ips
+}
@@ -0,0 +1,77 @@
+package avi
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDataSourceAviServiceEnginesRead(t *testing.T) {
+	var listQuery string
+	routes := testJSONHandler(map[string]string{
+		"/api/serviceenginegroup": `{"count": 1, "results": [{"uuid": "seg-1", "name": "Default-Group"}]}`,
+		"/api/serviceengine": `{"count": 3, "results": [
+			{"uuid": "se-1", "name": "se1", "url": "https://localhost/api/serviceengine/se-1#se1",
+				"se_group_ref": "https://localhost/api/serviceenginegroup/seg-1#Default-Group",
+				"cloud_ref": "https://localhost/api/cloud/cloud-1#Default-Cloud",
+				"mgmt_vnic": {"mac_address": "00:00:00:00:00:01", "vnic_networks": [
+					{"mode": "DHCP", "ip": {"ip_addr": {"addr": "10.10.1.5", "type": "V4"}, "mask": 24}}]},
+				"data_vnics": [{"mac_address": "00:00:00:00:00:02", "vnic_networks": [
+					{"mode": "STATIC", "ip": {"ip_addr": {"addr": "10.20.1.5", "type": "V4"}, "mask": 24}}]}],
+				"resources": {"num_vcpus": 2, "memory": 4096, "disk": 20}},
+			{"uuid": "se-2", "name": "other"},
+			{"uuid": "se-3", "name": "se3"}]}`,
+		"/api/serviceengine/se-1/runtime": `{"oper_status": {"state": "OPER_UP"}, "version": "17.2.1",
+			"se_connected": true, "power_state": "OPER_UP",
+			"vs_ref": ["https://localhost/api/virtualservice/vs-1#web", "https://localhost/api/virtualservice/vs-2"]}`,
+		"/api/serviceengine/se-3/runtime": `{"oper_status": {"state": "OPER_DOWN"}}`,
+		"/api/analytics/metrics/serviceengine/se-1": `{"series": [
+			{"header": {"name": "se_stats.avg_cpu_usage"}, "data": [{"value": 12.5}]},
+			{"header": {"name": "se_stats.avg_mem_usage"}, "data": [{"is_null": true, "value": 0}]}]}`,
+	})
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/serviceengine" {
+			listQuery = r.URL.RawQuery
+		}
+		routes(w, r)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviServiceEngines().TestResourceData()
+	d.Set("name_regex", "^se")
+	d.Set("se_group_ref", "/api/serviceenginegroup/?name=Default-Group")
+	d.Set("cloud_ref", "cloud-1")
+	if err := dataSourceAviServiceEnginesRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if expected := "include_name=true&se_group_ref.uuid=seg-1&cloud_ref.uuid=cloud-1"; listQuery != expected {
+		t.Errorf("query = %v, expected %v", listQuery, expected)
+	}
+	expected := map[string]interface{}{
+		"uuids.#":                                 2,
+		"names.0":                                 "se1",
+		"names.1":                                 "se3",
+		"mgmt_ips.#":                              2,
+		"mgmt_ips.0":                              "10.10.1.5",
+		"mgmt_ips.1":                              "",
+		"serviceengines.0.url":                    "https://localhost/api/serviceengine/se-1",
+		"serviceengines.0.se_group_ref":           "https://localhost/api/serviceenginegroup/seg-1",
+		"serviceengines.0.data_ips.0":             "10.20.1.5",
+		"serviceengines.0.oper_state":             "OPER_UP",
+		"serviceengines.0.connected":              true,
+		"serviceengines.0.version":                "17.2.1",
+		"serviceengines.0.virtualservice_refs.0":  "https://localhost/api/virtualservice/vs-1",
+		"serviceengines.0.virtualservice_names.#": 2,
+		"serviceengines.0.virtualservice_names.0": "web",
+		"serviceengines.0.virtualservice_names.1": "",
+		"serviceengines.0.num_vcpus":              2,
+		"serviceengines.0.memory":                 4096,
+		"serviceengines.0.cpu_usage":              12.5,
+		"serviceengines.0.memory_usage":           0.0,
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// vimgrInventoryFunc converts an object of a vimgr runtime collection into the
+// attributes of the inventory data source, or returns false to leave it out.
+type vimgrInventoryFunc func(d *schema.ResourceData, obj map[string]interface{}) (map[string]interface{}, bool, error)
+
+// dataSourceAviVimgrInventory returns a data source listing the objects of
+// the vimgr runtime collection objType, which the controller discovers in
+// vCenter clouds. The objects are exported under listKey with the attributes
+// of elem, which convert sets. extra adds filter arguments.
+func dataSourceAviVimgrInventory(objType string, listKey string, elem map[string]*schema.Schema,
+	extra map[string]*schema.Schema, convert vimgrInventoryFunc) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"cloud_ref": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"name_regex": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateRegexp,
+		},
+		"uuids": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"names": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"urls": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		listKey: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Resource{Schema: elem},
+		},
+	}
+	for _, k := range []string{"uuid", "name", "url"} {
+		elem[k] = &schema.Schema{
+			Type:     schema.TypeString,
+			Computed: true,
+		}
+	}
+	for k, v := range extra {
+		s[k] = v
+	}
+	return &schema.Resource{
+		Read: func(d *schema.ResourceData, meta interface{}) error {
+			return dataSourceAviVimgrInventoryRead(d, meta, objType, listKey, convert)
+		},
+		Schema: s,
+	}
+}
+
+func dataSourceAviVimgrInventoryRead(d *schema.ResourceData, meta interface{}, objType string, listKey string,
+	convert vimgrInventoryFunc) error {
+	cloudUUID := ""
+	if ref, ok := d.GetOk("cloud_ref"); ok {
+		var err error
+		if cloudUUID, err = refFilterUUID(meta, "cloud_ref", ref.(string), "cloud"); err != nil {
+			return err
+		}
+	}
+	path := objectsPath(objType, cloudUUID, nil)
+	var nameRe *regexp.Regexp
+	if nameRegex, ok := d.GetOk("name_regex"); ok {
+		nameRe = regexp.MustCompile(nameRegex.(string))
+	}
+	uuids := []string{}
+	names := []string{}
+	urls := []string{}
+	var objects []interface{}
+	err := ApiCollectionIterate(meta, path, func(obj map[string]interface{}) error {
+		name, _ := obj["name"].(string)
+		if nameRe != nil && !nameRe.MatchString(name) {
+			return nil
+		}
+		m, ok, err := convert(d, obj)
+		if err != nil || !ok {
+			return err
+		}
+		uuid, _ := obj["uuid"].(string)
+		objURL, _ := obj["url"].(string)
+		objURL = strings.SplitN(objURL, "#", 2)[0]
+		m["uuid"], m["name"], m["url"] = uuid, name, objURL
+		uuids = append(uuids, uuid)
+		names = append(names, name)
+		urls = append(urls, objURL)
+		objects = append(objects, m)
+		return nil
+	})
+	if err != nil {
+		log.Printf("[ERROR] dataSourceAviVimgrInventoryRead %v in listing %v\n", err, path)
+		return err
+	}
+	d.SetId(fmt.Sprintf("%v#%v", path, d.Get("name_regex")))
+	d.Set("uuids", uuids)
+	d.Set("names", names)
+	d.Set("urls", urls)
+	if err := d.Set(listKey, objects); err != nil {
+		log.Printf("[ERROR] dataSourceAviVimgrInventoryRead %v in setting %v\n", err, listKey)
+		return err
+	}
+	return nil
+}
+
+func computedStrings() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+func computedOf(t schema.ValueType) *schema.Schema {
+	return &schema.Schema{
+		Type:     t,
+		Computed: true,
+	}
+}
+
+// stripRefNames removes the names that include_name adds to references.
+func stripRefNames(refs []string) []string {
+	stripped := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		stripped = append(stripped, strings.SplitN(ref, "#", 2)[0])
+	}
+	return stripped
+}
+
+func dataSourceAviVcenterVMs() *schema.Resource {
+	return dataSourceAviVimgrInventory("vimgrvmruntime", "vms", map[string]*schema.Schema{
+		"host":         computedOf(schema.TypeString),
+		"powerstate":   computedOf(schema.TypeString),
+		"num_cpu":      computedOf(schema.TypeInt),
+		"memory":       computedOf(schema.TypeInt),
+		"ip_addresses": computedStrings(),
+		"nics": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"mac_addr":     computedOf(schema.TypeString),
+					"network_name": computedOf(schema.TypeString),
+					"network_uuid": computedOf(schema.TypeString),
+					"connected":    computedOf(schema.TypeBool),
+					"ip_addresses": computedStrings(),
+				},
+			},
+		},
+	}, map[string]*schema.Schema{
+		"ip_address": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+	}, func(d *schema.ResourceData, obj map[string]interface{}) (map[string]interface{}, bool, error) {
+		var vm models.VIMgrVMRuntime
+		if err := jsonToModel(obj, &vm); err != nil {
+			return nil, false, err
+		}
+		m := map[string]interface{}{}
+		setStringValue(m, "host", vm.Host)
+		setStringValue(m, "powerstate", vm.Powerstate)
+		if vm.NumCPU != nil {
+			m["num_cpu"] = int(*vm.NumCPU)
+		}
+		if vm.Memory != nil {
+			m["memory"] = int(*vm.Memory)
+		}
+		ipAddresses := []string{}
+		var nics []interface{}
+		for _, nic := range vm.GuestNic {
+			if nic == nil {
+				continue
+			}
+			nicIPs := []string{}
+			for _, ip := range nic.GuestIP {
+				if ip != nil && ip.Prefix != nil && ip.Prefix.IPAddr != nil && ip.Prefix.IPAddr.Addr != nil {
+					nicIPs = append(nicIPs, *ip.Prefix.IPAddr.Addr)
+				}
+			}
+			n := map[string]interface{}{
+				"ip_addresses": nicIPs,
+				"connected":    nic.Connected != nil && *nic.Connected,
+			}
+			setStringValue(n, "mac_addr", nic.MacAddr)
+			setStringValue(n, "network_name", nic.NetworkName)
+			setStringValue(n, "network_uuid", nic.NetworkUUID)
+			nics = append(nics, n)
+			ipAddresses = append(ipAddresses, nicIPs...)
+		}
+		if ip, ok := d.GetOk("ip_address"); ok {
+			found := false
+			for _, addr := range ipAddresses {
+				found = found || addr == ip.(string)
+			}
+			if !found {
+				return nil, false, nil
+			}
+		}
+		m["ip_addresses"] = ipAddresses
+		m["nics"] = nics
+		return m, true, nil
+	})
+}
+
+func dataSourceAviVcenterPortgroups() *schema.Resource {
+	return dataSourceAviVimgrInventory("vimgrnwruntime", "portgroups", map[string]*schema.Schema{
+		"switch_name":     computedOf(schema.TypeString),
+		"dvs":             computedOf(schema.TypeBool),
+		"vlan":            computedOf(schema.TypeInt),
+		"management":      computedOf(schema.TypeBool),
+		"vrf_context_ref": computedOf(schema.TypeString),
+		"cidrs":           computedStrings(),
+		"host_refs":       computedStrings(),
+		"vm_refs":         computedStrings(),
+	}, map[string]*schema.Schema{
+		"cidr": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateCIDROrIP,
+		},
+	}, func(d *schema.ResourceData, obj map[string]interface{}) (map[string]interface{}, bool, error) {
+		var nw models.VIMgrNWRuntime
+		if err := jsonToModel(obj, &nw); err != nil {
+			return nil, false, err
+		}
+		var subnets []interface{}
+		cidrs := []string{}
+		for _, subnet := range nw.IPSubnet {
+			if subnet == nil {
+				continue
+			}
+			if cidr := prefixCIDR(subnet.Prefix); cidr != "" {
+				cidrs = append(cidrs, cidr)
+				subnets = append(subnets, map[string]interface{}{"cidr": cidr})
+			}
+		}
+		if cidr, ok := d.GetOk("cidr"); ok && !subnetsContain(subnets, parseCIDROrIP(cidr.(string))) {
+			return nil, false, nil
+		}
+		m := map[string]interface{}{
+			"dvs":        nw.Dvs != nil && *nw.Dvs,
+			"management": nw.MgmtNW != nil && *nw.MgmtNW,
+			"cidrs":      cidrs,
+			"host_refs":  stripRefNames(nw.HostRefs),
+			"vm_refs":    stripRefNames(nw.VMRefs),
+		}
+		setStringValue(m, "switch_name", nw.SwitchName)
+		if nw.VrfContextRef != nil {
+			m["vrf_context_ref"] = strings.SplitN(*nw.VrfContextRef, "#", 2)[0]
+		}
+		if nw.Vlan != nil {
+			m["vlan"] = int(*nw.Vlan)
+		}
+		return m, true, nil
+	})
+}
+
+func dataSourceAviVcenterHosts() *schema.Resource {
+	return dataSourceAviVimgrInventory("vimgrhostruntime", "hosts", map[string]*schema.Schema{
+		"cluster_name":     computedOf(schema.TypeString),
+		"cluster_uuid":     computedOf(schema.TypeString),
+		"connection_state": computedOf(schema.TypeString),
+		"powerstate":       computedOf(schema.TypeString),
+		"maintenance_mode": computedOf(schema.TypeBool),
+		"num_cpu_cores":    computedOf(schema.TypeInt),
+		"mem":              computedOf(schema.TypeInt),
+		"network_uuids":    computedStrings(),
+		"vm_refs":          computedStrings(),
+	}, nil, func(d *schema.ResourceData, obj map[string]interface{}) (map[string]interface{}, bool, error) {
+		var host models.VIMgrHostRuntime
+		if err := jsonToModel(obj, &host); err != nil {
+			return nil, false, err
+		}
+		m := map[string]interface{}{
+			"maintenance_mode": host.MaintenanceMode != nil && *host.MaintenanceMode,
+			"network_uuids":    host.NetworkUuids,
+			"vm_refs":          stripRefNames(host.VMRefs),
+		}
+		setStringValue(m, "cluster_name", host.ClusterName)
+		setStringValue(m, "cluster_uuid", host.ClusterUUID)
+		setStringValue(m, "connection_state", host.ConnectionState)
+		setStringValue(m, "powerstate", host.Powerstate)
+		if host.NumCPUCores != nil {
+			m["num_cpu_cores"] = int(*host.NumCPUCores)
+		}
+		if host.Mem != nil {
+			m["mem"] = int(*host.Mem)
+		}
+		return m, true, nil
+	})
+}
+
+func dataSourceAviVcenterClusters() *schema.Resource {
+	return dataSourceAviVimgrInventory("vimgrclusterruntime", "clusters", map[string]*schema.Schema{
+		"datacenter_uuid": computedOf(schema.TypeString),
+		"host_refs":       computedStrings(),
+	}, nil, func(d *schema.ResourceData, obj map[string]interface{}) (map[string]interface{}, bool, error) {
+		var cluster models.VIMgrClusterRuntime
+		if err := jsonToModel(obj, &cluster); err != nil {
+			return nil, false, err
+		}
+		m := map[string]interface{}{"host_refs": stripRefNames(cluster.HostRefs)}
+		setStringValue(m, "datacenter_uuid", cluster.DatacenterUUID)
+		return m, true, nil
+	})
+}
@@ -0,0 +1,85 @@
+package avi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDataSourceAviVcenterInventoryRead(t *testing.T) {
+	queries := make(map[string]string)
+	routes := testJSONHandler(map[string]string{
+		"/api/vimgrvmruntime": `{"count": 2, "results": [
+			{"uuid": "vm-1", "name": "web-1", "url": "https://localhost/api/vimgrvmruntime/vm-1#web-1",
+				"host": "esx-1", "powerstate": "POWERED_ON", "num_cpu": 2, "memory": 4096,
+				"guest_nic": [{"mac_addr": "00:50:56:00:00:01", "network_name": "pg-web", "network_uuid": "dvportgroup-1",
+					"connected": true,
+					"guest_ip": [{"prefix": {"ip_addr": {"addr": "10.10.1.11", "type": "V4"}, "mask": 24}}]}]},
+			{"uuid": "vm-2", "name": "db-1", "url": "https://localhost/api/vimgrvmruntime/vm-2#db-1"}]}`,
+		"/api/vimgrnwruntime": `{"count": 2, "results": [
+			{"uuid": "dvportgroup-1", "name": "pg-web", "url": "https://localhost/api/vimgrnwruntime/dvportgroup-1",
+				"dvs": true, "vlan": 110, "switch_name": "dvs-1",
+				"ip_subnet": [{"prefix": {"ip_addr": {"addr": "10.10.1.0", "type": "V4"}, "mask": 24}}],
+				"vm_refs": ["https://localhost/api/vimgrvmruntime/vm-1#web-1"]},
+			{"uuid": "dvportgroup-2", "name": "pg-db"}]}`,
+		"/api/vimgrhostruntime": `{"count": 1, "results": [
+			{"uuid": "host-1", "name": "esx-1", "cluster_name": "prod", "connection_state": "connected",
+				"num_cpu_cores": 16, "mem": 137438953472}]}`,
+		"/api/vimgrclusterruntime": `{"count": 1, "results": [
+			{"uuid": "domain-c1", "name": "prod", "host_refs": ["https://localhost/api/vimgrhostruntime/host-1#esx-1"]}]}`,
+	})
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		queries[r.URL.Path] = r.URL.RawQuery
+		routes(w, r)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	cases := []struct {
+		ds       *schema.Resource
+		args     map[string]string
+		expected map[string]interface{}
+	}{
+		{dataSourceAviVcenterVMs(), map[string]string{"name_regex": "^web-", "cloud_ref": "cloud-1"},
+			map[string]interface{}{
+				"urls.#":                 1,
+				"urls.0":                 "https://localhost/api/vimgrvmruntime/vm-1",
+				"vms.0.powerstate":       "POWERED_ON",
+				"vms.0.memory":           4096,
+				"vms.0.ip_addresses.0":   "10.10.1.11",
+				"vms.0.nics.0.connected": true,
+			}},
+		{dataSourceAviVcenterVMs(), map[string]string{"ip_address": "10.10.1.11"},
+			map[string]interface{}{"names.#": 1, "names.0": "web-1"}},
+		{dataSourceAviVcenterPortgroups(), map[string]string{"cidr": "10.10.1.0/24"},
+			map[string]interface{}{
+				"uuids.#":                1,
+				"portgroups.0.dvs":       true,
+				"portgroups.0.vlan":      110,
+				"portgroups.0.cidrs.0":   "10.10.1.0/24",
+				"portgroups.0.vm_refs.0": "https://localhost/api/vimgrvmruntime/vm-1",
+			}},
+		{dataSourceAviVcenterHosts(), map[string]string{},
+			map[string]interface{}{"hosts.0.cluster_name": "prod", "hosts.0.num_cpu_cores": 16}},
+		{dataSourceAviVcenterClusters(), map[string]string{},
+			map[string]interface{}{"clusters.0.host_refs.0": "https://localhost/api/vimgrhostruntime/host-1"}},
+	}
+	for _, c := range cases {
+		d := c.ds.TestResourceData()
+		for k, v := range c.args {
+			d.Set(k, v)
+		}
+		if err := c.ds.Read(d, client); err != nil {
+			t.Fatalf("%v: err: %s", c.args, err)
+		}
+		if c.args["cloud_ref"] != "" && queries["/api/vimgrvmruntime"] != "cloud_ref.uuid=cloud-1&include_name=true" {
+			t.Errorf("%v: query = %v, expected a cloud filter", c.args, queries["/api/vimgrvmruntime"])
+		}
+		for k, v := range c.expected {
+			if actual := d.Get(k); actual != v {
+				t.Errorf("%v: %v = %v, expected %v", c.args, k, actual, v)
+			}
+		}
+	}
+}
@@ -10,7 +10,7 @@ import "github.com/hashicorp/terraform/helper/schema"
 func dataSourceAviVirtualService() *schema.Resource {
 	return &schema.Resource{
 		Read: ResourceAviVirtualServiceRead,
-		Schema: map[string]*schema.Schema{
+		Schema: withAllocatedVip(map[string]*schema.Schema{
 			"active_standby_se_tag": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -350,6 +350,6 @@ func dataSourceAviVirtualService() *schema.Resource {
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
-		},
+		}),
 	}
 }
@@ -0,0 +1,256 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"strings"
+)
+
+// aviOperStatus is the operational status reported by runtime endpoints.
+type aviOperStatus struct {
+	State  string   `json:"state"`
+	Reason []string `json:"reason"`
+}
+
+// aviVsRuntimeSummary is the part of api/virtualservice/<uuid>/runtime that
+// avi_virtualservice_runtime exports.
+type aviVsRuntimeSummary struct {
+	OperStatus   aviOperStatus `json:"oper_status"`
+	PercentSesUp float64       `json:"percent_ses_up"`
+	VipSummary   []struct {
+		VipID         string `json:"vip_id"`
+		ServiceEngine []struct {
+			URL       string `json:"url"`
+			Primary   bool   `json:"primary"`
+			Standby   bool   `json:"standby"`
+			Connected bool   `json:"connected"`
+		} `json:"service_engine"`
+	} `json:"vip_summary"`
+}
+
+type aviIPAddr struct {
+	Addr string `json:"addr"`
+	Type string `json:"type"`
+}
+
+// aviVip is a vip of a virtual service or vsvip.
+type aviVip struct {
+	VipID       string     `json:"vip_id"`
+	IPAddress   *aviIPAddr `json:"ip_address"`
+	IP6Address  *aviIPAddr `json:"ip6_address"`
+	FloatingIP  *aviIPAddr `json:"floating_ip"`
+	FloatingIP6 *aviIPAddr `json:"floating_ip6"`
+}
+
+func dataSourceAviVirtualServiceRuntime() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAviVirtualServiceRuntimeRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"uuid": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"cloud_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"tenant_ref": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"oper_state": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"oper_reasons": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"percent_ses_up": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"service_engines": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vip_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"se_ref": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"se_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"primary": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"standby": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"connected": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"vips": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vip_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip6_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"floating_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"floating_ip6": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"vip_addresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"floating_ip_addresses": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceAviVirtualServiceRuntimeRead(d *schema.ResourceData, meta interface{}) error {
+	sess := aviSession(meta)
+	uuid, err := dataSourceObjectUUID(d, meta, "virtualservice")
+	if err != nil {
+		return err
+	}
+	var vs struct {
+		Name      string   `json:"name"`
+		CloudRef  string   `json:"cloud_ref"`
+		TenantRef string   `json:"tenant_ref"`
+		VsvipRef  string   `json:"vsvip_ref"`
+		Vip       []aviVip `json:"vip"`
+	}
+	path := "api/virtualservice/" + uuid
+	if err := sess.Get(path, &vs); err != nil {
+		log.Printf("[ERROR] dataSourceAviVirtualServiceRuntimeRead %v in GET of %v\n", err, path)
+		return err
+	}
+	vips := vs.Vip
+	if vs.VsvipRef != "" {
+		var vsvip struct {
+			Vip []aviVip `json:"vip"`
+		}
+		path := "api/vsvip/" + UUIDFromID(vs.VsvipRef)
+		if err := sess.Get(path, &vsvip); err != nil {
+			log.Printf("[ERROR] dataSourceAviVirtualServiceRuntimeRead %v in GET of %v\n", err, path)
+			return err
+		}
+		vips = vsvip.Vip
+	}
+	var runtime aviVsRuntimeSummary
+	path = "api/virtualservice/" + uuid + "/runtime?include_name=true"
+	if err := sess.Get(path, &runtime); err != nil {
+		log.Printf("[ERROR] dataSourceAviVirtualServiceRuntimeRead %v in GET of %v\n", err, path)
+		return err
+	}
+
+	var serviceEngines []interface{}
+	for _, vip := range runtime.VipSummary {
+		for _, se := range vip.ServiceEngine {
+			seRef, seName := se.URL, UUIDFromID(se.URL)
+			if parts := strings.SplitN(se.URL, "#", 2); len(parts) == 2 {
+				seRef, seName = parts[0], parts[1]
+			}
+			serviceEngines = append(serviceEngines, map[string]interface{}{
+				"vip_id":    vip.VipID,
+				"se_ref":    seRef,
+				"se_name":   seName,
+				"primary":   se.Primary,
+				"standby":   se.Standby,
+				"connected": se.Connected,
+			})
+		}
+	}
+	var vipList []interface{}
+	vipAddresses := []string{}
+	floatingIPAddresses := []string{}
+	for _, vip := range vips {
+		m := map[string]interface{}{"vip_id": vip.VipID}
+		for key, addr := range map[string]*aviIPAddr{"ip_address": vip.IPAddress, "ip6_address": vip.IP6Address,
+			"floating_ip": vip.FloatingIP, "floating_ip6": vip.FloatingIP6} {
+			if addr != nil {
+				m[key] = addr.Addr
+			}
+		}
+		for _, addr := range []*aviIPAddr{vip.IPAddress, vip.IP6Address} {
+			if addr != nil && addr.Addr != "" {
+				vipAddresses = append(vipAddresses, addr.Addr)
+			}
+		}
+		for _, addr := range []*aviIPAddr{vip.FloatingIP, vip.FloatingIP6} {
+			if addr != nil && addr.Addr != "" {
+				floatingIPAddresses = append(floatingIPAddresses, addr.Addr)
+			}
+		}
+		vipList = append(vipList, m)
+	}
+
+	d.SetId(uuid)
+	d.Set("uuid", uuid)
+	d.Set("name", vs.Name)
+	d.Set("cloud_ref", vs.CloudRef)
+	d.Set("tenant_ref", vs.TenantRef)
+	d.Set("oper_state", runtime.OperStatus.State)
+	d.Set("oper_reasons", runtime.OperStatus.Reason)
+	d.Set("percent_ses_up", int(runtime.PercentSesUp))
+	if err := d.Set("service_engines", serviceEngines); err != nil {
+		return err
+	}
+	if err := d.Set("vips", vipList); err != nil {
+		return err
+	}
+	d.Set("vip_addresses", vipAddresses)
+	d.Set("floating_ip_addresses", floatingIPAddresses)
+	return nil
+}
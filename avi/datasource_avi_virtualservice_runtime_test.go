@@ -0,0 +1,55 @@
+package avi
+
+import (
+	"testing"
+)
+
+func TestDataSourceAviVirtualServiceRuntimeRead(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/virtualservice": `{"count": 1, "results": [{"uuid": "vs-1", "name": "web"}]}`,
+		"/api/virtualservice/vs-1": `{"uuid": "vs-1", "name": "web",
+			"cloud_ref": "https://localhost/api/cloud/cloud-1",
+			"vsvip_ref": "https://localhost/api/vsvip/vsvip-1"}`,
+		"/api/vsvip/vsvip-1": `{"vip": [{"vip_id": "0",
+			"ip_address": {"addr": "10.0.0.10", "type": "V4"},
+			"floating_ip": {"addr": "192.168.1.10", "type": "V4"}}]}`,
+		"/api/virtualservice/vs-1/runtime": `{"oper_status": {"state": "OPER_PARTITIONED", "reason": ["SE down"]},
+			"percent_ses_up": 50,
+			"vip_summary": [{"vip_id": "0", "service_engine": [
+				{"url": "https://localhost/api/serviceengine/se-1#se1", "primary": true, "connected": true},
+				{"url": "https://localhost/api/serviceengine/se-2#se2", "standby": true}]}]}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := dataSourceAviVirtualServiceRuntime().TestResourceData()
+	d.Set("name", "web")
+	if err := dataSourceAviVirtualServiceRuntimeRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"uuid":                      "vs-1",
+		"oper_state":                "OPER_PARTITIONED",
+		"oper_reasons.0":            "SE down",
+		"percent_ses_up":            50,
+		"service_engines.#":         2,
+		"service_engines.0.se_ref":  "https://localhost/api/serviceengine/se-1",
+		"service_engines.0.se_name": "se1",
+		"service_engines.0.primary": true,
+		"service_engines.1.standby": true,
+		"vips.0.ip_address":         "10.0.0.10",
+		"vip_addresses.0":           "10.0.0.10",
+		"floating_ip_addresses.0":   "192.168.1.10",
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+
+	d = dataSourceAviVirtualServiceRuntime().TestResourceData()
+	d.Set("uuid", "vs-2")
+	if err := dataSourceAviVirtualServiceRuntimeRead(d, client); err == nil {
+		t.Fatalf("expected an error for a missing virtual service")
+	}
+}
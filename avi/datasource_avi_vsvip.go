@@ -10,7 +10,7 @@ import "github.com/hashicorp/terraform/helper/schema"
 func dataSourceAviVsVip() *schema.Resource {
 	return &schema.Resource{
 		Read: ResourceAviVsVipRead,
-		Schema: map[string]*schema.Schema{
+		Schema: withAllocatedVip(map[string]*schema.Schema{
 			"cloud_ref": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -57,6 +57,6 @@ func dataSourceAviVsVip() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
-		},
+		}),
 	}
 }
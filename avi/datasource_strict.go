@@ -0,0 +1,129 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// addStrictDataSourceReads makes the singular object data sources, except the
+// given ones, fail when no object matches instead of returning an empty id,
+// and when a name matches objects in several tenants or clouds that the
+// tenant_ref and cloud_ref arguments do not tell apart.
+func addStrictDataSourceReads(dataSources map[string]*schema.Resource, except ...string) {
+	skip := make(map[string]bool)
+	for _, name := range except {
+		skip[name] = true
+	}
+	for name, r := range dataSources {
+		objType := strings.TrimPrefix(name, "avi_")
+		if skip[name] || IsPostNotAllowed(objType) {
+			continue
+		}
+		r.Read = strictDataSourceRead(objType, r.Read)
+	}
+}
+
+// strictDataSourceRead wraps the read of the data source of objType. A lookup
+// by name is resolved to a single uuid before read is called.
+func strictDataSourceRead(objType string, read schema.ReadFunc) schema.ReadFunc {
+	return func(d *schema.ResourceData, meta interface{}) error {
+		_, hasUUID := d.GetOk("uuid")
+		name, hasName := d.GetOk("name")
+		if !hasUUID && hasName {
+			uuid, err := dataSourceFindByName(d, meta, objType, name.(string))
+			if err != nil {
+				return err
+			}
+			d.Set("uuid", uuid)
+		}
+		if err := read(d, meta); err != nil {
+			return err
+		}
+		if d.Id() == "" {
+			if hasUUID || hasName {
+				return fmt.Errorf("no %v found with %v", objType, dataSourceLookupDesc(d))
+			}
+			return fmt.Errorf("no %v found, set name or uuid to select one", objType)
+		}
+		return nil
+	}
+}
+
+// dataSourceFindByName returns the uuid of the only object of objType with
+// the given name in the tenant_ref and cloud_ref of d, when they are set.
+func dataSourceFindByName(d *schema.ResourceData, meta interface{}, objType string, name string) (string, error) {
+	path := "api/" + objType + "?name=" + url.QueryEscape(name) + "&include_name=true&skip_default=true"
+	objs, err := ApiCollectionGetAll(meta, path)
+	if err != nil {
+		log.Printf("[ERROR] dataSourceFindByName %v in GET of %v\n", err, path)
+		return "", err
+	}
+	var matched []map[string]interface{}
+	for _, obj := range objs {
+		if dataSourceRefFilterMatches(d, obj, "tenant_ref") && dataSourceRefFilterMatches(d, obj, "cloud_ref") {
+			matched = append(matched, obj)
+		}
+	}
+	if len(matched) == 0 {
+		return "", fmt.Errorf("no %v found with %v", objType, dataSourceLookupDesc(d))
+	}
+	if len(matched) > 1 {
+		var where []string
+		for _, obj := range matched {
+			where = append(where, dataSourceObjectPlacement(obj))
+		}
+		return "", fmt.Errorf("%d objects of type %v found with %v (%v), set tenant_ref or cloud_ref to select one",
+			len(matched), objType, dataSourceLookupDesc(d), strings.Join(where, "; "))
+	}
+	uuid, _ := matched[0]["uuid"].(string)
+	return uuid, nil
+}
+
+// dataSourceRefFilterMatches reports whether the reference key of obj points
+// to the object set for key in d. It is true when d does not set key.
+func dataSourceRefFilterMatches(d *schema.ResourceData, obj map[string]interface{}, key string) bool {
+	wanted, ok := d.GetOk(key)
+	if !ok {
+		return true
+	}
+	actual, _ := obj[key].(string)
+	return jsonRefMatches(actual, wanted.(string))
+}
+
+// dataSourceObjectPlacement describes the tenant and cloud of obj by name.
+func dataSourceObjectPlacement(obj map[string]interface{}) string {
+	var placement []string
+	for _, key := range []string{"tenant_ref", "cloud_ref"} {
+		if ref, ok := obj[key].(string); ok {
+			refName := UUIDFromID(ref)
+			if parts := strings.SplitN(ref, "#", 2); len(parts) == 2 {
+				refName = parts[1]
+			}
+			placement = append(placement, strings.TrimSuffix(key, "_ref")+" "+refName)
+		}
+	}
+	if len(placement) == 0 {
+		uuid, _ := obj["uuid"].(string)
+		return uuid
+	}
+	return strings.Join(placement, ", ")
+}
+
+// dataSourceLookupDesc describes the lookup arguments set in d.
+func dataSourceLookupDesc(d *schema.ResourceData) string {
+	var desc []string
+	for _, key := range []string{"uuid", "name", "tenant_ref", "cloud_ref"} {
+		if v, ok := d.GetOk(key); ok {
+			desc = append(desc, fmt.Sprintf("%v %q", key, v))
+		}
+	}
+	return strings.Join(desc, ", ")
+}
@@ -0,0 +1,96 @@
+package avi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestStrictDataSourceRead(t *testing.T) {
+	requests := 0
+	pools := []map[string]interface{}{
+		{"uuid": "pool-1", "name": "web", "url": "https://localhost/api/pool/pool-1",
+			"tenant_ref": "https://localhost/api/tenant/admin#admin", "cloud_ref": "https://localhost/api/cloud/cloud-1#c1"},
+		{"uuid": "pool-2", "name": "web", "url": "https://localhost/api/pool/pool-2",
+			"tenant_ref": "https://localhost/api/tenant/admin#admin", "cloud_ref": "https://localhost/api/cloud/cloud-2#c2"},
+		{"uuid": "pool-3", "name": "app", "url": "https://localhost/api/pool/pool-3"},
+	}
+	collection := testCollectionHandler(t, pools, &requests)
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/pool" {
+			collection(w, r)
+			return
+		}
+		for _, pool := range pools {
+			if r.URL.Path == "/api/pool/"+pool["uuid"].(string) {
+				json.NewEncoder(w).Encode(pool)
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	read := Provider().(*schema.Provider).DataSourcesMap["avi_pool"].Read
+	cases := []struct {
+		args  map[string]string
+		uuid  string
+		error string
+	}{
+		{map[string]string{"name": "app"}, "pool-3", ""},
+		{map[string]string{"name": "web", "cloud_ref": "/api/cloud/?name=c2"}, "pool-2", ""},
+		{map[string]string{"name": "web", "cloud_ref": "https://localhost/api/cloud/cloud-1"}, "pool-1", ""},
+		{map[string]string{"uuid": "pool-1"}, "pool-1", ""},
+		{map[string]string{"name": "web"}, "", "2 objects of type pool found"},
+		{map[string]string{"name": "web", "tenant_ref": "/api/tenant/?name=admin"}, "", "cloud c1; tenant admin, cloud c2"},
+		{map[string]string{"name": "missing"}, "", `no pool found with name "missing"`},
+		{map[string]string{"name": "app", "cloud_ref": "/api/cloud/?name=c1"}, "", "no pool found"},
+		{map[string]string{"uuid": "pool-9"}, "", `no pool found with uuid "pool-9"`},
+	}
+	for _, c := range cases {
+		d := dataSourceAviPool().TestResourceData()
+		for k, v := range c.args {
+			d.Set(k, v)
+		}
+		err := read(d, client)
+		if c.error != "" {
+			if err == nil || !strings.Contains(err.Error(), c.error) {
+				t.Errorf("%v: expected error %q, got %v", c.args, c.error, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: err: %s", c.args, err)
+		} else if uuid := d.Get("uuid"); uuid != c.uuid {
+			t.Errorf("%v: expected %v, got %v", c.args, c.uuid, uuid)
+		}
+	}
+}
+
+func TestStrictDataSourceReadServer(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{
+		"/api/pool/pool-1": `{"uuid": "pool-1", "name": "web", "servers": [{"ip": {"addr": "10.0.0.1", "type": "V4"}}]}`,
+	}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	read := Provider().(*schema.Provider).DataSourcesMap["avi_server"].Read
+	d := dataSourceAviServer().TestResourceData()
+	d.Set("pool_ref", "https://localhost/api/pool/pool-1")
+	d.Set("ip", "10.0.0.1")
+	if err := read(d, client); err != nil || d.Id() != "pool-1:10.0.0.1:0" {
+		t.Errorf("id = %v, err = %v, expected the server of the pool", d.Id(), err)
+	}
+
+	d = dataSourceAviServer().TestResourceData()
+	d.Set("pool_ref", "https://localhost/api/pool/pool-1")
+	d.Set("ip", "10.0.0.2")
+	err := read(d, client)
+	if err == nil || !strings.Contains(err.Error(), `no server found in pool_ref "https://localhost/api/pool/pool-1" with ip "10.0.0.2"`) {
+		t.Errorf("err = %v, expected the missing server to be named", err)
+	}
+}
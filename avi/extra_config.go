@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// extraConfigJSONKey is the attribute of every typed resource that holds a JSON
+// overlay for fields the generated schema does not model yet.
+const extraConfigJSONKey = "extra_config_json"
+
+// addExtraConfigJSON adds extra_config_json to the resources written through
+// SchemaToAviData and ApiRead, that is all resources except the given ones.
+func addExtraConfigJSON(resources map[string]*schema.Resource, except ...string) {
+	skip := make(map[string]bool)
+	for _, name := range except {
+		skip[name] = true
+	}
+	for name, r := range resources {
+		if skip[name] {
+			continue
+		}
+		r.Schema[extraConfigJSONKey] = extraConfigJSONSchema(r.Schema)
+	}
+}
+
+// extraConfigJSONSchema returns the extra_config_json attribute of a resource
+// with schema s. Overlay fields that s already models are rejected during
+// validation so that a field is never owned by both.
+func extraConfigJSONSchema(s map[string]*schema.Schema) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		ValidateFunc: func(v interface{}, k string) (ws []string, es []error) {
+			var overlay map[string]interface{}
+			if err := json.Unmarshal([]byte(v.(string)), &overlay); err != nil {
+				return nil, []error{fmt.Errorf("%q must be a JSON object: %v", k, err)}
+			}
+			for _, field := range extraConfigConflicts(overlay, s, "") {
+				es = append(es, fmt.Errorf("%q sets %v, which is an attribute of the resource; set it there instead", k, field))
+			}
+			return
+		},
+		DiffSuppressFunc: suppressJSONSubsetDiff,
+	}
+}
+
+// extraConfigConflicts returns the fields of overlay that s models. Objects
+// that s models are descended into, so an overlay may add unmodeled fields to
+// them.
+func extraConfigConflicts(overlay map[string]interface{}, s map[string]*schema.Schema, prefix string) []string {
+	var conflicts []string
+	for k, v := range overlay {
+		ks, ok := s[k]
+		if !ok {
+			continue
+		}
+		elem, isObject := ks.Elem.(*schema.Resource)
+		vmap, isMap := v.(map[string]interface{})
+		if ks.Type == schema.TypeSet && isObject && isMap {
+			conflicts = append(conflicts, extraConfigConflicts(vmap, elem.Schema, prefix+k+".")...)
+			continue
+		}
+		conflicts = append(conflicts, prefix+k)
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// mergeExtraConfigJSON deep-merges the extra_config_json overlay of d into
+// data, the payload built from the typed attributes.
+func mergeExtraConfigJSON(data map[string]interface{}, d *schema.ResourceData) error {
+	v, ok := d.GetOk(extraConfigJSONKey)
+	if !ok {
+		return nil
+	}
+	var overlay map[string]interface{}
+	if err := json.Unmarshal([]byte(v.(string)), &overlay); err != nil {
+		return fmt.Errorf("%v must be a JSON object: %v", extraConfigJSONKey, err)
+	}
+	mergeJSONObject(data, overlay)
+	return nil
+}
+
+// mergeJSONObject merges overlay into dst. Objects present in both are merged
+// recursively, any other overlay value replaces the one of dst.
+func mergeJSONObject(dst map[string]interface{}, overlay map[string]interface{}) {
+	for k, v := range overlay {
+		vmap, ok := v.(map[string]interface{})
+		dmap, dok := dst[k].(map[string]interface{})
+		if ok && dok {
+			mergeJSONObject(dmap, vmap)
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+// setExtraConfigJSON records the fields of obj covered by the configured
+// extra_config_json overlay, so that only those fields are checked for drift.
+func setExtraConfigJSON(d *schema.ResourceData, obj interface{}) {
+	v, ok := d.GetOk(extraConfigJSONKey)
+	if !ok {
+		return
+	}
+	var overlay interface{}
+	if err := json.Unmarshal([]byte(v.(string)), &overlay); err != nil {
+		return
+	}
+	state, err := json.Marshal(jsonSubset(obj, overlay))
+	if err != nil {
+		log.Printf("[ERROR] setExtraConfigJSON %v in encoding %v\n", err, obj)
+		return
+	}
+	d.Set(extraConfigJSONKey, string(state))
+}
+
+// suppressJSONSubsetDiff ignores differences in formatting, key order and the
+// form of references. The old value is stored with only the keys of the
+// previous configuration, see setExtraConfigJSON and ResourceAviRestObjectRead,
+// so a removed key is still reported as a change.
+func suppressJSONSubsetDiff(k, old, new string, d *schema.ResourceData) bool {
+	var oldObj, newObj interface{}
+	if err := json.Unmarshal([]byte(old), &oldObj); err != nil {
+		return false
+	}
+	if err := json.Unmarshal([]byte(new), &newObj); err != nil {
+		return false
+	}
+	return jsonKeysIn(oldObj, newObj) && reflect.DeepEqual(jsonSubset(oldObj, newObj), newObj)
+}
+
+// jsonKeysIn reports whether every key of the objects in old, at every level,
+// is also a key of the matching object in new.
+func jsonKeysIn(old interface{}, new interface{}) bool {
+	switch o := old.(type) {
+	case map[string]interface{}:
+		n, ok := new.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for k, ov := range o {
+			nv, ok := n[k]
+			if !ok || !jsonKeysIn(ov, nv) {
+				return false
+			}
+		}
+	case []interface{}:
+		n, ok := new.([]interface{})
+		if !ok || len(n) != len(o) {
+			return false
+		}
+		for i := range o {
+			if !jsonKeysIn(o[i], n[i]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// jsonSubset returns the parts of actual that wanted specifies. Keys of actual
+// that are absent from wanted are dropped at every level, lists of the same
+// length are projected element by element, and a reference in actual that
+// points to the object wanted refers to is returned as written in wanted.
+func jsonSubset(actual interface{}, wanted interface{}) interface{} {
+	switch w := wanted.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			return actual
+		}
+		m := make(map[string]interface{})
+		for k, wv := range w {
+			if av, ok := a[k]; ok {
+				m[k] = jsonSubset(av, wv)
+			}
+		}
+		return m
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok || len(a) != len(w) {
+			return actual
+		}
+		l := make([]interface{}, len(a))
+		for i := range a {
+			l[i] = jsonSubset(a[i], w[i])
+		}
+		return l
+	case string:
+		if a, ok := actual.(string); ok && jsonRefMatches(a, w) {
+			return w
+		}
+	}
+	return actual
+}
+
+// jsonRefMatches reports whether the reference actual, as returned by the
+// controller, points to the object of the reference wanted. wanted may use the
+// uuid or the ?name= form.
+func jsonRefMatches(actual string, wanted string) bool {
+	if actual == wanted {
+		return true
+	}
+	ai := strings.Index(actual, "/api/")
+	wi := strings.Index(wanted, "/api/")
+	if ai < 0 || wi < 0 {
+		return false
+	}
+	aType := strings.SplitN(actual[ai+len("/api/"):], "/", 2)[0]
+	wPath := wanted[wi+len("/api/"):]
+	if i := strings.Index(wPath, "?"); i >= 0 {
+		query, err := url.ParseQuery(wPath[i+1:])
+		wType := strings.TrimSuffix(wPath[:i], "/")
+		if err != nil || wType != aType {
+			return false
+		}
+		name := query.Get("name")
+		return name != "" && strings.HasSuffix(actual, "#"+name)
+	}
+	wType := strings.SplitN(wPath, "/", 2)[0]
+	return wType == aType && UUIDFromID(actual) == UUIDFromID(wanted)
+}
@@ -0,0 +1,96 @@
+package avi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func testTenantResource() *schema.Resource {
+	return Provider().(*schema.Provider).ResourcesMap["avi_tenant"]
+}
+
+func TestExtraConfigJSONValidate(t *testing.T) {
+	s := testTenantResource().Schema[extraConfigJSONKey]
+	cases := []struct {
+		overlay string
+		errors  int
+	}{
+		{`{"labels": [{"key": "a"}]}`, 0},
+		{`{"config_settings": {"se_in_provider_context": false}}`, 1},
+		{`{"config_settings": {"new_setting": true}}`, 0},
+		{`{"name": "x", "description": "y"}`, 2},
+		{`[1, 2]`, 1},
+	}
+	for _, c := range cases {
+		if _, es := s.ValidateFunc(c.overlay, extraConfigJSONKey); len(es) != c.errors {
+			t.Errorf("validate %v: expected %d errors, got %v", c.overlay, c.errors, es)
+		}
+	}
+	if _, ok := Provider().(*schema.Provider).ResourcesMap["avi_server"].Schema[extraConfigJSONKey]; ok {
+		t.Fatalf("avi_server does not write through SchemaToAviData and must not have %v", extraConfigJSONKey)
+	}
+}
+
+func TestExtraConfigJSONMergeAndRead(t *testing.T) {
+	var put map[string]interface{}
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tenant/tenant-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == "PUT" {
+			put = nil
+			if err := json.NewDecoder(r.Body).Decode(&put); err != nil {
+				t.Errorf("err: %s", err)
+			}
+			put["uuid"] = "tenant-1"
+			put["labels"] = []interface{}{map[string]interface{}{"key": "a", "value": "default"}}
+			put["unrelated"] = "added by the controller"
+		}
+		json.NewEncoder(w).Encode(put)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := testTenantResource().TestResourceData()
+	d.Set("uuid", "tenant-1")
+	d.Set("name", "t1")
+	d.Set("config_settings", []interface{}{map[string]interface{}{"tenant_vrf": true}})
+	d.Set(extraConfigJSONKey, `{"labels": [{"key": "a"}], "config_settings": {"new_setting": true}}`)
+	if err := resourceAviTenantUpdate(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	settings := put["config_settings"].(map[string]interface{})
+	if settings["tenant_vrf"] != true || settings["new_setting"] != true {
+		t.Fatalf("overlay not merged into config_settings: %v", settings)
+	}
+	if put["name"] != "t1" {
+		t.Fatalf("unexpected object %v", put)
+	}
+	expected := `{"config_settings":{"new_setting":true},"labels":[{"key":"a"}]}`
+	if v := d.Get(extraConfigJSONKey); v != expected {
+		t.Fatalf("expected %v to track only the overlay fields, got %v", extraConfigJSONKey, v)
+	}
+}
+
+func TestExtraConfigJSONDiff(t *testing.T) {
+	stored := `{"config_settings":{"new_setting":true},"labels":[{"key":"a"}]}`
+	cases := []struct {
+		new      string
+		suppress bool
+	}{
+		{`{"labels": [{"key": "a"}], "config_settings": {"new_setting": true}}`, true},
+		{`{"labels": [{"key": "a"}], "config_settings": {"new_setting": false}}`, false},
+		// removed keys are changes.
+		{`{"labels": [{"key": "a"}]}`, false},
+		{`{"labels": [{"key": "a"}], "config_settings": {}}`, false},
+	}
+	for _, c := range cases {
+		if suppress := suppressJSONSubsetDiff(extraConfigJSONKey, stored, c.new, nil); suppress != c.suppress {
+			t.Errorf("suppressJSONSubsetDiff(%v) = %v, expected %v", c.new, suppress, c.suppress)
+		}
+	}
+}
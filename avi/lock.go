@@ -0,0 +1,56 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"sync"
+)
+
+// keyedMutex is a set of mutexes identified by a key such as an object uuid.
+// Entries exist only while a goroutine holds or waits for them.
+type keyedMutex struct {
+	lock    sync.Mutex
+	entries map[string]*keyedMutexEntry
+}
+
+type keyedMutexEntry struct {
+	sync.Mutex
+	// refs is the number of goroutines holding or waiting for the entry.
+	refs int
+}
+
+// objectLocks serializes the read-modify-write sequences the provider runs on
+// one object, for example adding a server to a pool, against each other.
+var objectLocks = newKeyedMutex()
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{entries: map[string]*keyedMutexEntry{}}
+}
+
+// Lock locks the mutex of key.
+func (k *keyedMutex) Lock(key string) {
+	k.lock.Lock()
+	entry, ok := k.entries[key]
+	if !ok {
+		entry = &keyedMutexEntry{}
+		k.entries[key] = entry
+	}
+	entry.refs++
+	k.lock.Unlock()
+	entry.Lock()
+}
+
+// Unlock unlocks the mutex of key.
+func (k *keyedMutex) Unlock(key string) {
+	k.lock.Lock()
+	entry := k.entries[key]
+	entry.refs--
+	if entry.refs == 0 {
+		delete(k.entries, key)
+	}
+	k.lock.Unlock()
+	entry.Unlock()
+}
@@ -0,0 +1,123 @@
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestKeyedMutex(t *testing.T) {
+	locks := newKeyedMutex()
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	inside := map[string]int{}
+	for i := 0; i < 20; i++ {
+		key := fmt.Sprintf("obj-%d", i%2)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			locks.Lock(key)
+			defer locks.Unlock(key)
+			lock.Lock()
+			inside[key]++
+			if inside[key] > 1 {
+				t.Errorf("%v locked twice", key)
+			}
+			lock.Unlock()
+			time.Sleep(time.Millisecond)
+			lock.Lock()
+			inside[key]--
+			lock.Unlock()
+		}()
+	}
+	wg.Wait()
+	if len(locks.entries) != 0 {
+		t.Fatalf("expected no entries left, got %v", locks.entries)
+	}
+}
+
+// testPoolServerHandler serves one pool and applies PATCH add and delete of
+// its servers like the controller does.
+func testPoolServerHandler(t *testing.T, patches *int) http.HandlerFunc {
+	var lock sync.Mutex
+	servers := []interface{}{}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/pool/p1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == "PATCH" {
+			var patch map[string]map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+				t.Errorf("err: %s", err)
+			}
+			// a slow controller lets more servers join the next batch.
+			time.Sleep(20 * time.Millisecond)
+			lock.Lock()
+			*patches++
+			if add, ok := patch["add"]; ok {
+				servers = append(servers, add["servers"].([]interface{})...)
+			}
+			lock.Unlock()
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		pool := map[string]interface{}{
+			"uuid":    "p1",
+			"name":    "pool1",
+			"url":     "https://localhost/api/pool/p1",
+			"servers": servers,
+		}
+		if err := json.NewEncoder(w).Encode(pool); err != nil {
+			t.Errorf("err: %s", err)
+		}
+	}
+}
+
+func TestPoolServerBatchesConcurrentCreates(t *testing.T) {
+	patches := 0
+	client, server := newTestAviClient(t, testPoolServerHandler(t, &patches))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		d := resourceAviServer().TestResourceData()
+		d.Set("pool_ref", "https://localhost/api/pool/p1")
+		d.Set("ip", fmt.Sprintf("10.0.0.%d", i))
+		d.Set("port", 80)
+		d.Set("type", "V4")
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := resourceAviServerCreateOrUpdate(d, client); err != nil {
+				t.Errorf("err: %s", err)
+			}
+			if d.Id() == "" {
+				t.Errorf("server %v not found after create", d.Get("ip"))
+			}
+		}()
+	}
+	wg.Wait()
+	if patches >= 10 {
+		t.Fatalf("expected servers to be batched, got %d PATCHes", patches)
+	}
+}
+
+func TestResourceAviServerDeleteWithoutPool(t *testing.T) {
+	client, server := newTestAviClient(t, testJSONHandler(map[string]string{}))
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviServer().TestResourceData()
+	d.SetId("p1:10.0.0.1:80")
+	d.Set("pool_ref", "https://localhost/api/pool/p1")
+	d.Set("ip", "10.0.0.1")
+	d.Set("port", 80)
+	if err := resourceAviServerDelete(d, client); err != nil || d.Id() != "" {
+		t.Errorf("id = %q, err = %v, expected the server of a deleted pool to be removed", d.Id(), err)
+	}
+}
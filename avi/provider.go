@@ -13,10 +13,11 @@ import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hashicorp/terraform/terraform"
 	"log"
+	"sync"
 )
 
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"avi_username": &schema.Schema{
 				Type:        schema.TypeString,
@@ -54,6 +55,24 @@ func Provider() terraform.ResourceProvider {
 				DefaultFunc: schema.EnvDefaultFunc("AVI_AUTHTOKEN", nil),
 				Description: "Avi token for Avi Controller.",
 			},
+			"api_page_size": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AVI_API_PAGE_SIZE", defaultApiPageSize),
+				Description: "Number of objects requested per page when reading collections.",
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AVI_MAX_CONCURRENT_REQUESTS", defaultMaxConcurrentRequests),
+				Description: "Maximum number of requests sent to the Avi Controller at the same time.",
+			},
+			"enable_cache": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("AVI_ENABLE_CACHE", false),
+				Description: "Read whole collections once per object type and serve refreshes from memory.",
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource{
 			"avi_useraccountprofile":            dataSourceAviUserAccountProfile(),
@@ -205,9 +224,46 @@ func Provider() terraform.ResourceProvider {
 			"avi_useraccount":                   resourceAviUserAccount(),
 			"avi_fileservice":                   resourceAviFileService(),
 			"avi_server":                        resourceAviServer(),
+			"avi_rest_object":                   resourceAviRestObject(),
+			"avi_license":                       resourceAviLicense(),
+			"avi_network_ip_reservation":        resourceAviNetworkIPReservation(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
+	// avi_server is looked up by pool_ref and ip and fails on its own.
+	addStrictDataSourceReads(p.DataSourcesMap, "avi_fileservice", "avi_server")
+	// avi_serviceengines is a runtime data source.
+	addListDataSources(p.DataSourcesMap, "avi_fileservice", "avi_server", "avi_serviceengine")
+	for name, r := range runtimeDataSources() {
+		p.DataSourcesMap[name] = r
+	}
+	// resources with their own API handling do not support the overlay.
+	addExtraConfigJSON(p.ResourcesMap, "avi_useraccount", "avi_fileservice", "avi_server", "avi_rest_object",
+		"avi_license", "avi_network_ip_reservation")
+	return p
+}
+
+// runtimeDataSources returns the data sources of runtime and inventory
+// endpoints. They do not read objects of a type, so they are added after the
+// singular and plural object data sources have been set up.
+func runtimeDataSources() map[string]*schema.Resource {
+	return map[string]*schema.Resource{
+		"avi_rest":                   dataSourceAviRest(),
+		"avi_virtualservice_runtime": dataSourceAviVirtualServiceRuntime(),
+		"avi_pool_server_health":     dataSourceAviPoolServerHealth(),
+		"avi_metrics":                dataSourceAviMetrics(),
+		"avi_application_logs":       dataSourceAviApplicationLogs(),
+		"avi_serviceengines":         dataSourceAviServiceEngines(),
+		"avi_cluster_runtime":        dataSourceAviClusterRuntime(),
+		"avi_controllerlicense":      dataSourceAviControllerLicense(),
+		"avi_cloud_runtime":          dataSourceAviCloudRuntime(),
+		"avi_cloud_networks":         dataSourceAviCloudNetworks(),
+		"avi_vcenter_vms":            dataSourceAviVcenterVMs(),
+		"avi_vcenter_portgroups":     dataSourceAviVcenterPortgroups(),
+		"avi_vcenter_hosts":          dataSourceAviVcenterHosts(),
+		"avi_vcenter_clusters":       dataSourceAviVcenterClusters(),
+		"avi_network_free_ips":       dataSourceAviNetworkFreeIPs(),
+	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
@@ -234,19 +290,117 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 		return nil, err
 	}
 
-	aviClient, err := clients.NewAviClient(
-		config.Controller, config.Username,
+	if maxRequests := d.Get("max_concurrent_requests").(int); maxRequests < 1 {
+		return nil, fmt.Errorf("max_concurrent_requests must be at least 1, got %d", maxRequests)
+	}
+
+	// every session, including the one of the client, logs in through the
+	// transport of the session pool.
+	login := &sync.Mutex{}
+	sessionOptions := []func(*session.AviSession) error{
 		session.SetPassword(config.Password),
 		session.SetTenant(config.Tenant),
 		session.SetVersion(config.Version),
 		session.SetAuthToken(config.AuthToken),
-		session.SetInsecure)
+		session.SetInsecure,
+		session.SetTransport(newLoginTransport(login)),
+	}
+	aviClient, err := clients.NewAviClient(config.Controller, config.Username, sessionOptions...)
 
 	log.Printf("Avi Client created for user %s tenant %s version %s\n",
 		config.Username, config.Tenant, config.Version)
+	if err == nil {
+		settings := newProviderSettings(aviClient)
+		settings.pageSize = d.Get("api_page_size").(int)
+		settings.sessions = newAviSessionPool(aviClient.AviSession, d.Get("max_concurrent_requests").(int),
+			config.Tenant, func() (*session.AviSession, error) {
+				return session.NewAviSession(config.Controller, config.Username, sessionOptions...)
+			}, login)
+		if d.Get("enable_cache").(bool) {
+			settings.cache = newObjectCache()
+		}
+		setProviderSettings(aviClient, settings)
+	}
 	return aviClient, err
 }
 
+// providerSettings holds the provider options that are not part of the SDK
+// session. Resources receive the *clients.AviClient as meta, so the settings
+// are looked up by the client they were configured with.
+type providerSettings struct {
+	pageSize int
+	// sessions serves every API call of the provider.
+	sessions *aviSessionPool
+	// cache is nil unless enable_cache is set.
+	cache *objectCache
+}
+
+// newProviderSettings returns the default settings for client, which send
+// one request at a time on the session of the client.
+func newProviderSettings(client *clients.AviClient) *providerSettings {
+	return &providerSettings{
+		pageSize: defaultApiPageSize,
+		sessions: newAviSessionPool(client.AviSession, 1, session.DEFAULT_API_TENANT, nil, nil),
+	}
+}
+
+var (
+	providerSettingsLock sync.RWMutex
+	providerSettingsMap  = map[*clients.AviClient]*providerSettings{}
+)
+
+func setProviderSettings(client *clients.AviClient, settings *providerSettings) {
+	providerSettingsLock.Lock()
+	defer providerSettingsLock.Unlock()
+	providerSettingsMap[client] = settings
+}
+
+// getProviderSettings returns the settings of the provider that created meta.
+// Clients that were not created by providerConfigure get the defaults.
+func getProviderSettings(meta interface{}) *providerSettings {
+	if m, ok := meta.(*tenantMeta); ok {
+		settings := *getProviderSettings(m.client)
+		settings.sessions = settings.sessions.inTenant(m.tenant)
+		// the cache holds the objects of the tenant of the provider.
+		settings.cache = nil
+		return &settings
+	}
+	client := meta.(*clients.AviClient)
+	providerSettingsLock.RLock()
+	settings, ok := providerSettingsMap[client]
+	providerSettingsLock.RUnlock()
+	if ok {
+		return settings
+	}
+	providerSettingsLock.Lock()
+	defer providerSettingsLock.Unlock()
+	if settings, ok = providerSettingsMap[client]; !ok {
+		settings = newProviderSettings(client)
+		providerSettingsMap[client] = settings
+	}
+	return settings
+}
+
+// tenantMeta is the meta of a provider whose API calls are sent in another
+// tenant than the one of the provider.
+type tenantMeta struct {
+	client *clients.AviClient
+	tenant string
+}
+
+// withTenant returns meta with its API calls sent in tenant. Objects of other
+// tenants are not visible to, and can not be written in, the tenant of the
+// provider. An empty tenant returns meta as is.
+func withTenant(meta interface{}, tenant string) interface{} {
+	if tenant == "" {
+		return meta
+	}
+	if m, ok := meta.(*tenantMeta); ok {
+		meta = m.client
+	}
+	return &tenantMeta{client: meta.(*clients.AviClient), tenant: tenant}
+}
+
 type Credentials struct {
 	Username   string
 	Password   string
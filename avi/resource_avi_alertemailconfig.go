@@ -6,7 +6,6 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strings"
@@ -94,11 +93,9 @@ func resourceAviAlertEmailConfigDelete(d *schema.ResourceData, meta interface{})
 	if ApiDeleteSystemDefaultCheck(d) {
 		return nil
 	}
-	client := meta.(*clients.AviClient)
 	uuid := d.Get("uuid").(string)
 	if uuid != "" {
-		path := "api/" + objType + "/" + uuid
-		err := client.AviSession.Delete(path)
+		err := ApiDelete(meta, objType, uuid)
 		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
 			log.Println("[INFO] resourceAviAlertEmailConfigDelete not found")
 			return err
@@ -6,10 +6,11 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strings"
+	"time"
 )
 
 func ResourceCloudSchema() map[string]*schema.Schema {
@@ -193,13 +194,57 @@ func resourceAviCloud() *schema.Resource {
 		Read:   ResourceAviCloudRead,
 		Update: resourceAviCloudUpdate,
 		Delete: resourceAviCloudDelete,
-		Schema: ResourceCloudSchema(),
+		Schema: withCloudWait(ResourceCloudSchema()),
 		Importer: &schema.ResourceImporter{
 			State: ResourceCloudImporter,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
 	}
 }
 
+// withCloudWait adds the attribute that makes create and update wait for the
+// cloud to be ready for placement. It is not part of the cloud object.
+func withCloudWait(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["wait_for_ready"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+	return s
+}
+
+// waitForCloudReady waits up to timeout for the cloud of d to be ready for
+// placement, when wait_for_ready is set. It fails as soon as the cloud
+// connector reports a failure. When before is set, the status read before an
+// update, a ready or failed status is only accepted once a status has
+// changed since before, as the first polls may still return the status from
+// before the update.
+func waitForCloudReady(d *schema.ResourceData, meta interface{}, timeout time.Duration, before *aviCloudStatus) error {
+	if !d.Get("wait_for_ready").(bool) {
+		return nil
+	}
+	uuid := UUIDFromID(d.Id())
+	changed := before == nil
+	return waitFor("cloud "+uuid+" to be ready", timeout, func() (bool, string, error) {
+		status, err := getCloudStatus(meta, uuid)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		changed = changed || status.changedSince(before)
+		if status.State == "CLOUD_STATE_FAILED" && changed {
+			return false, "", fmt.Errorf("cloud connector failed: %v", status.Reason)
+		}
+		state := "state " + status.State
+		if status.Reason != "" {
+			state += ", reason " + status.Reason
+		}
+		return status.ready() && changed, state, nil
+	})
+}
+
 func ResourceCloudImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	s := ResourceCloudSchema()
 	return ResourceImporter(d, m, "cloud", s)
@@ -220,16 +265,28 @@ func resourceAviCloudCreate(d *schema.ResourceData, meta interface{}) error {
 	if err == nil {
 		err = ResourceAviCloudRead(d, meta)
 	}
+	if err == nil {
+		err = waitForCloudReady(d, meta, d.Timeout(schema.TimeoutCreate), nil)
+	}
 	return err
 }
 
 func resourceAviCloudUpdate(d *schema.ResourceData, meta interface{}) error {
 	s := ResourceCloudSchema()
 	var err error
+	var before *aviCloudStatus
+	if d.Get("wait_for_ready").(bool) {
+		if before, err = getCloudStatus(meta, UUIDFromID(d.Id())); err != nil {
+			log.Printf("[ERROR] resourceAviCloudUpdate in reading the cloud status: %v\n", err)
+		}
+	}
 	err = ApiCreateOrUpdate(d, meta, "cloud", s)
 	if err == nil {
 		err = ResourceAviCloudRead(d, meta)
 	}
+	if err == nil {
+		err = waitForCloudReady(d, meta, d.Timeout(schema.TimeoutUpdate), before)
+	}
 	return err
 }
 
@@ -238,11 +295,9 @@ func resourceAviCloudDelete(d *schema.ResourceData, meta interface{}) error {
 	if ApiDeleteSystemDefaultCheck(d) {
 		return nil
 	}
-	client := meta.(*clients.AviClient)
 	uuid := d.Get("uuid").(string)
 	if uuid != "" {
-		path := "api/" + objType + "/" + uuid
-		err := client.AviSession.Delete(path)
+		err := ApiDelete(meta, objType, uuid)
 		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
 			log.Println("[INFO] resourceAviCloudDelete not found")
 			return err
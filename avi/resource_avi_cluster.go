@@ -6,7 +6,6 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strings"
@@ -57,6 +56,10 @@ func resourceAviCluster() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			State: ResourceClusterImporter,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+		},
 	}
 }
 
@@ -77,9 +80,10 @@ func ResourceAviClusterRead(d *schema.ResourceData, meta interface{}) error {
 func resourceAviClusterCreate(d *schema.ResourceData, meta interface{}) error {
 	s := ResourceClusterSchema()
 	err := ApiCreateOrUpdate(d, meta, "cluster", s)
-	// Added wait for cluster initialization process as cluster initialization starts after few seconds.
-	// This is necessary to store correct state of initialized cluster.
-	time.Sleep(90 * time.Second)
+	// the initialized cluster is read, so that its state is stored.
+	if err == nil {
+		err = waitForClusterReady(d, meta, d.Timeout(schema.TimeoutCreate))
+	}
 	if err == nil {
 		err = ResourceAviClusterRead(d, meta)
 	}
@@ -90,25 +94,76 @@ func resourceAviClusterUpdate(d *schema.ResourceData, meta interface{}) error {
 	s := ResourceClusterSchema()
 	var err error
 	err = ApiCreateOrUpdate(d, meta, "cluster", s)
-	// Added wait for cluster initialization process as cluster initialization starts after few seconds.
-	// This is necessary to store correct state of initialized cluster.
-	time.Sleep(90 * time.Second)
+	// the initialized cluster is read, so that its state is stored.
+	if err == nil {
+		err = waitForClusterReady(d, meta, d.Timeout(schema.TimeoutUpdate))
+	}
 	if err == nil {
 		err = ResourceAviClusterRead(d, meta)
 	}
 	return err
 }
 
+// waitForClusterReady waits until the nodes of the cluster are the nodes of
+// d, every one of them active, and the cluster is up, with high availability
+// when it has several nodes. The runtime read right after a change may still
+// be the ready runtime of the previous nodes, which the node set tells apart.
+// Nodes restart services while they join, so failures to read the cluster
+// runtime are retried until timeout. Without nodes in d, only the cluster
+// is waited for.
+func waitForClusterReady(d *schema.ResourceData, meta interface{}, timeout time.Duration) error {
+	var nodes []struct {
+		IP aviIPAddr `json:"ip"`
+	}
+	if err := schemaToModel(d.Get("nodes"), &nodes); err != nil {
+		return err
+	}
+	return waitFor("cluster to be up", timeout, func() (bool, string, error) {
+		runtime, err := getClusterRuntime(meta)
+		if err != nil {
+			return false, err.Error(), nil
+		}
+		state := "state " + runtime.ClusterState.State
+		if runtime.ClusterState.Reason != "" {
+			state += ", reason " + runtime.ClusterState.Reason
+		}
+		ready := runtime.ready()
+		if len(nodes) > 1 && runtime.ClusterState.State != "CLUSTER_UP_HA_ACTIVE" {
+			ready = false
+		}
+		// nodes are named "node" by default, they are told apart by address.
+		configured := make(map[string]bool)
+		for _, node := range nodes {
+			configured[node.IP.Addr] = true
+			active := false
+			for _, n := range runtime.NodeStates {
+				if n.MgmtIP == node.IP.Addr && n.State == "CLUSTER_ACTIVE" {
+					active = true
+				}
+			}
+			if !active {
+				ready = false
+				state += ", node " + node.IP.Addr + " not active"
+			}
+		}
+		for _, n := range runtime.NodeStates {
+			if len(nodes) > 0 && !configured[n.MgmtIP] {
+				ready = false
+				state += ", node " + n.MgmtIP + " not removed"
+			}
+		}
+		return ready, state, nil
+	})
+}
+
 func resourceAviClusterDelete(d *schema.ResourceData, meta interface{}) error {
 	objType := "cluster"
 	if ApiDeleteSystemDefaultCheck(d) {
 		return nil
 	}
-	client := meta.(*clients.AviClient)
 	uuid := d.Get("uuid").(string)
 	if uuid != "" {
-		path := "api/" + objType + "/" + uuid
-		err := client.AviSession.Delete(path)
+		err := ApiDelete(meta, objType, uuid)
 		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
 			log.Println("[INFO] resourceAviClusterDelete not found")
 			return err
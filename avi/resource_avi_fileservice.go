@@ -1,7 +1,7 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"os"
@@ -15,8 +15,9 @@ func ResourceFileServiceSchema() map[string]*schema.Schema {
 			Required: true,
 		},
 		"local_file": &schema.Schema{
-			Type:     schema.TypeString,
-			Required: true,
+			Type:             schema.TypeString,
+			Required:         true,
+			DiffSuppressFunc: suppressImportedFileServiceLicenseDiff,
 		},
 		//upload flag to state current local file will be uploaded to remote server.
 		"upload": &schema.Schema{
@@ -41,19 +42,75 @@ func resourceAviFileService() *schema.Resource {
 }
 
 func ResourceFileServiceImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if strings.HasPrefix(d.Id(), "license:") {
+		return resourceAviFileServiceLicenseImporter(d, m)
+	}
 	s := ResourceFileServiceSchema()
 	return ResourceImporter(d, m, "fileservice", s)
 }
 
+// resourceAviFileServiceLicenseImporter adopts an installed license using an
+// ID of the form license:<license id or name>. The local_file the license was
+// read from is not known to the controller and has to be set in the config.
+func resourceAviFileServiceLicenseImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	id := strings.TrimPrefix(d.Id(), "license:")
+	license, err := fileServiceFindLicense(m, id)
+	if err != nil {
+		return nil, err
+	}
+	if license == nil {
+		return nil, fmt.Errorf("license %v not found on the controller", id)
+	}
+	log.Printf("[DEBUG] resourceAviFileServiceLicenseImporter found license %v\n", license)
+	d.Set("uri", "license")
+	d.Set("upload", true)
+	d.SetId(id)
+	return []*schema.ResourceData{d}, nil
+}
+
+// suppressImportedFileServiceLicenseDiff ignores the local_file of the config
+// for imported licenses, which have none in their state, instead of uploading
+// the license again.
+func suppressImportedFileServiceLicenseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return d.Id() != "" && old == "" && d.Get("uri").(string) == "license"
+}
+
+// fileServiceFindLicense looks up an installed license by its license_id or
+// by its name with white space removed, which is how
+// MultipartUploadOrDownload records the ID of an uploaded license.
+func fileServiceFindLicense(meta interface{}, id string) (map[string]interface{}, error) {
+	sess := aviSession(meta)
+	var res interface{}
+	path := "/api/license"
+	if err := sess.Get(path, &res); err != nil {
+		log.Printf("[ERROR] fileServiceFindLicense %v in GET of path %v\n", err, path)
+		return nil, err
+	}
+	licenses, _ := res.(map[string]interface{})["licenses"].([]interface{})
+	for _, l := range licenses {
+		license, ok := l.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if licenseID, ok := license["license_id"].(string); ok && licenseID == id {
+			return license, nil
+		}
+		if name, ok := license["license_name"].(string); ok && strings.Join(strings.Fields(name), "") == id {
+			return license, nil
+		}
+	}
+	return nil, nil
+}
+
 func ResourceAviFileServiceRead(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	var res interface{}
 	switch upload := d.Get("upload").(bool); upload {
 	case true:
 		switch uri := d.Get("uri").(string); uri {
 		case "license":
 			path := "/api/license"
-			err := client.AviSession.Get(path, &res)
+			err := sess.Get(path, &res)
 			log.Printf("[DEBUG] ResourceAviFileServiceRead response: %v\n\n", res)
 			if err != nil {
 				log.Printf("[ERROR] ResourceAviFileServiceRead %v in GET of path %v\n", err, path)
@@ -63,7 +120,7 @@ func ResourceAviFileServiceRead(d *schema.ResourceData, meta interface{}) error
 			uri := strings.Split(d.Get("uri").(string), "?")[0]
 			path := "/api/fileservice?uri=controller://" + uri
 			log.Printf("[DEBUG] ResourceAviFileServiceRead reading fileservice API status path %v\n", path)
-			err := client.AviSession.Get(path, &res)
+			err := sess.Get(path, &res)
 			log.Printf("[DEBUG] ResourceAviFileServiceRead response: %v\n\n", res)
 			if err != nil {
 				log.Printf("[ERROR] ResourceAviFileServiceRead %v in GET of path %v\n", err, path)
@@ -109,14 +166,14 @@ func ResourceAviFileServiceUpdate(d *schema.ResourceData, meta interface{}) erro
 }
 
 func ResourceAviFileServiceDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	local_file := d.Get("local_file").(string)
 	switch upload := d.Get("upload").(bool); upload {
 	case true:
 		switch uri := d.Get("uri").(string); uri {
 		case "license":
 			path := "/api/" + uri + "/" + d.Id()
-			err := client.AviSession.Delete(path)
+			err := sess.Delete(path)
 			if err != nil {
 				log.Printf("[ERROR] ResourceAviFileServiceDelete %v Deleting file of path %v\n", err, path)
 			}
@@ -124,7 +181,7 @@ func ResourceAviFileServiceDelete(d *schema.ResourceData, meta interface{}) erro
 			uri := strings.Split(d.Get("uri").(string), "?")[0]
 			path := "/api/fileservice?uri=controller://" + uri + "/" + d.Id()
 			log.Printf("[DEBUG] ResourceAviFileServiceDelete deleting file using fileservice API status path %v\n", path)
-			err := client.AviSession.Delete(path)
+			err := sess.Delete(path)
 			if err != nil {
 				log.Printf("[ERROR] ResourceAviFileServiceDelete %v Deleting file of path %v\n", err, path)
 				return err
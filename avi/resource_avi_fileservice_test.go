@@ -0,0 +1,75 @@
+package avi
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceAviFileServiceLicenseImporter(t *testing.T) {
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		// the license path is requested as //api/license.
+		if strings.TrimLeft(r.URL.Path, "/") != "api/license" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"licenses": []map[string]interface{}{
+			{"license_id": "eval", "license_name": "Trial"},
+			{"license_id": "lic-1", "license_name": "Example Enterprise"},
+		}})
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	// licenses are found by id and by name without white space.
+	for _, id := range []string{"lic-1", "ExampleEnterprise"} {
+		d := resourceAviFileService().TestResourceData()
+		d.SetId("license:" + id)
+		if _, err := ResourceFileServiceImporter(d, client); err != nil {
+			t.Errorf("%v: err: %s", id, err)
+			continue
+		}
+		if d.Id() != id || d.Get("uri") != "license" || d.Get("upload") != true {
+			t.Errorf("%v: imported id %v uri %v upload %v", id, d.Id(), d.Get("uri"), d.Get("upload"))
+		}
+	}
+
+	d := resourceAviFileService().TestResourceData()
+	d.SetId("license:Example Enterprise")
+	_, err := ResourceFileServiceImporter(d, client)
+	if err == nil || !strings.Contains(err.Error(), "license Example Enterprise not found") {
+		t.Errorf("err = %v, expected the license not to be found", err)
+	}
+}
+
+func TestResourceAviFileServiceImportedLicenseDiff(t *testing.T) {
+	raw, err := config.NewRawConfig(map[string]interface{}{"uri": "license", "local_file": "/tmp/license.lic"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c := terraform.NewResourceConfig(raw)
+
+	// imported licenses have no local_file in their state.
+	imported := &terraform.InstanceState{ID: "lic-1", Attributes: map[string]string{
+		"uri": "license", "upload": "true"}}
+	diff, err := resourceAviFileService().Diff(imported, c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("expected no diff after import, got %v", diff)
+	}
+
+	uploaded := &terraform.InstanceState{ID: "lic-1", Attributes: map[string]string{
+		"uri": "license", "upload": "true", "local_file": "/tmp/old.lic"}}
+	diff, err = resourceAviFileService().Diff(uploaded, c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil || diff.Attributes["local_file"] == nil {
+		t.Errorf("expected a changed local_file to be uploaded, got %v", diff)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"io/ioutil"
+	"log"
+	"strings"
+)
+
+// ResourceLicenseSchema is the schema of avi_license, which applies license
+// text and tracks the licenses it added by their license ids.
+func ResourceLicenseSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"license_text": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			ConflictsWith:    []string{"local_file"},
+			DiffSuppressFunc: suppressImportedLicenseDiff,
+		},
+		"local_file": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ConflictsWith:    []string{"license_text"},
+			DiffSuppressFunc: suppressImportedLicenseDiff,
+		},
+		"license_ids": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"licenses": {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem: &schema.Resource{
+				Schema: withLicenseLimits(map[string]*schema.Schema{
+					"license_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"license_name": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"license_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"tier_type": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"start_on": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"valid_until": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				}),
+			},
+		},
+	}
+}
+
+func resourceAviLicense() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAviLicenseCreate,
+		Read:   ResourceAviLicenseRead,
+		Update: resourceAviLicenseUpdate,
+		Delete: resourceAviLicenseDelete,
+		Schema: ResourceLicenseSchema(),
+		Importer: &schema.ResourceImporter{
+			State: ResourceLicenseImporter,
+		},
+	}
+}
+
+// ResourceLicenseImporter imports installed licenses by their license ids,
+// separated by commas. The license text is not known to the controller, the
+// license_text or local_file of the config is not applied to imported
+// licenses.
+func ResourceLicenseImporter(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	if err := ResourceAviLicenseRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() != id {
+		return nil, fmt.Errorf("licenses %v not found on the controller", id)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceAviLicenseCreate(d *schema.ResourceData, meta interface{}) error {
+	ids, err := applyLicense(d, meta)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("the license text did not add a license on the controller, " +
+			"import installed licenses by their license ids instead")
+	}
+	d.SetId(strings.Join(ids, ","))
+	return ResourceAviLicenseRead(d, meta)
+}
+
+// resourceAviLicenseUpdate applies changed license text, which renews the
+// tracked licenses or adds licenses to them.
+func resourceAviLicenseUpdate(d *schema.ResourceData, meta interface{}) error {
+	ids, err := applyLicense(d, meta)
+	if err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		d.SetId(d.Id() + "," + strings.Join(ids, ","))
+	}
+	return ResourceAviLicenseRead(d, meta)
+}
+
+// applyLicense applies the license text of d and returns the ids of the
+// licenses that were not installed before. Renewing an installed license
+// does not add its id. The licenses are compared before and after the text
+// is applied, other avi_license resources must not add or delete licenses in
+// between.
+func applyLicense(d *schema.ResourceData, meta interface{}) ([]string, error) {
+	text := d.Get("license_text").(string)
+	if localFile, ok := d.GetOk("local_file"); ok {
+		data, err := ioutil.ReadFile(localFile.(string))
+		if err != nil {
+			log.Printf("[ERROR] applyLicense %v in reading %v\n", err, localFile)
+			return nil, err
+		}
+		text = string(data)
+	}
+	if text == "" {
+		return nil, fmt.Errorf("one of license_text or local_file must be set")
+	}
+	objectLocks.Lock("license")
+	defer objectLocks.Unlock("license")
+	before, err := getControllerLicense(meta)
+	if err != nil {
+		return nil, err
+	}
+	var res interface{}
+	path := "api/license"
+	if err := aviSession(meta).Put(path, map[string]string{"license_text": text}, &res); err != nil {
+		log.Printf("[ERROR] applyLicense %v in PUT of %v\n", err, path)
+		return nil, err
+	}
+	log.Printf("[DEBUG] applyLicense response: %v\n", res)
+	after, err := getControllerLicense(meta)
+	if err != nil {
+		return nil, err
+	}
+	installed := make(map[string]bool)
+	for _, l := range before.Licenses {
+		if l.LicenseID != nil {
+			installed[*l.LicenseID] = true
+		}
+	}
+	var ids []string
+	for _, l := range after.Licenses {
+		if l.LicenseID != nil && !installed[*l.LicenseID] {
+			ids = append(ids, *l.LicenseID)
+		}
+	}
+	return ids, nil
+}
+
+func ResourceAviLicenseRead(d *schema.ResourceData, meta interface{}) error {
+	license, err := getControllerLicense(meta)
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*models.SingleLicense)
+	for _, l := range license.Licenses {
+		if l.LicenseID != nil {
+			byID[*l.LicenseID] = l
+		}
+	}
+	// licenses removed on the controller are no longer tracked. When all of
+	// them are removed, the license text is applied again.
+	var ids []string
+	var licenses []interface{}
+	for _, id := range strings.Split(d.Id(), ",") {
+		l, ok := byID[id]
+		if !ok {
+			log.Printf("[INFO] ResourceAviLicenseRead license %v not found\n", id)
+			continue
+		}
+		ids = append(ids, id)
+		licenses = append(licenses, singleLicenseAttributes(l))
+	}
+	if len(ids) == 0 {
+		d.SetId("")
+		return nil
+	}
+	d.SetId(strings.Join(ids, ","))
+	d.Set("license_ids", ids)
+	if err := d.Set("licenses", licenses); err != nil {
+		log.Printf("[ERROR] ResourceAviLicenseRead %v in setting licenses\n", err)
+		return err
+	}
+	return nil
+}
+
+func resourceAviLicenseDelete(d *schema.ResourceData, meta interface{}) error {
+	objectLocks.Lock("license")
+	defer objectLocks.Unlock("license")
+	for _, id := range strings.Split(d.Id(), ",") {
+		path := "api/license/" + id
+		err := aviSession(meta).Delete(path)
+		if err != nil && !strings.Contains(err.Error(), "404") {
+			log.Printf("[ERROR] resourceAviLicenseDelete %v in DELETE of %v\n", err, path)
+			return err
+		}
+	}
+	d.SetId("")
+	return nil
+}
+
+// suppressImportedLicenseDiff ignores the license text of the config for
+// imported licenses, which have none in their state, instead of applying it.
+func suppressImportedLicenseDiff(k, old, new string, d *schema.ResourceData) bool {
+	oldText, _ := d.GetChange("license_text")
+	oldFile, _ := d.GetChange("local_file")
+	return d.Id() != "" && oldText.(string) == "" && oldFile.(string) == ""
+}
@@ -0,0 +1,171 @@
+package avi
+
+import (
+	"encoding/json"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestResourceAviLicenseLifecycle(t *testing.T) {
+	licenses := []map[string]interface{}{
+		{"license_id": "eval", "license_name": "Trial", "valid_until": "2017-08-01 00:00:00"},
+	}
+	var deleted []string
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/license" && r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"licenses": licenses})
+		case r.URL.Path == "/api/license" && r.Method == "PUT":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			if body["license_text"] != "LICENSE" {
+				t.Errorf("unexpected license text %q", body["license_text"])
+			}
+			// the license is installed once, applying it again renews it.
+			if len(licenses) == 1 {
+				licenses = append(licenses, map[string]interface{}{"license_id": "lic-1",
+					"license_name": "Example Enterprise", "cores": 16, "valid_until": "2018-07-01 00:00:00"})
+			} else {
+				licenses[1]["valid_until"] = "2019-07-01 00:00:00"
+			}
+			json.NewEncoder(w).Encode(map[string]string{"result": "License Example Enterprise added"})
+		case strings.HasPrefix(r.URL.Path, "/api/license/") && r.Method == "DELETE":
+			deleted = append(deleted, strings.TrimPrefix(r.URL.Path, "/api/license/"))
+			licenses = licenses[:1]
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviLicense().TestResourceData()
+	d.Set("license_text", "LICENSE")
+	if err := resourceAviLicenseCreate(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"license_ids.#":          1,
+		"license_ids.0":          "lic-1",
+		"licenses.0.cores":       16,
+		"licenses.0.valid_until": "2018-07-01 00:00:00",
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+
+	d2 := resourceAviLicense().TestResourceData()
+	d2.Set("license_text", "LICENSE")
+	if err := resourceAviLicenseCreate(d2, client); err == nil || !strings.Contains(err.Error(), "did not add") {
+		t.Errorf("expected an error for a license text that renews an installed license, got %v", err)
+	}
+	if d2.Id() != "" {
+		t.Errorf("expected the renewed license not to be tracked, got %v", d2.Id())
+	}
+
+	if err := resourceAviLicenseDelete(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "lic-1" {
+		t.Errorf("deleted %v, expected only lic-1", deleted)
+	}
+
+	// a license removed on the controller is applied again.
+	d.SetId("lic-1")
+	if err := ResourceAviLicenseRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the removed license to clear the id, got %v", d.Id())
+	}
+
+	// licenses that are still installed stay tracked.
+	d.SetId("eval,lic-1")
+	if err := ResourceAviLicenseRead(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Id() != "eval" || d.Get("license_ids.#") != 1 {
+		t.Errorf("expected only eval to be tracked, got %v", d.Id())
+	}
+}
+
+func TestResourceAviLicenseParallelCreate(t *testing.T) {
+	var lock sync.Mutex
+	licenses := []map[string]interface{}{}
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		switch {
+		case r.URL.Path == "/api/license" && r.Method == "GET":
+			json.NewEncoder(w).Encode(map[string]interface{}{"licenses": licenses})
+		case r.URL.Path == "/api/license" && r.Method == "PUT":
+			var body map[string]string
+			json.NewDecoder(r.Body).Decode(&body)
+			licenses = append(licenses, map[string]interface{}{"license_id": "lic-" + body["license_text"]})
+			json.NewEncoder(w).Encode(map[string]string{"result": "License added"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	testProviderSettings(client, 0).sessions = newAviSessionPool(client.AviSession, 4, "admin", nil, nil)
+
+	// each resource tracks only the license its text added.
+	var wg sync.WaitGroup
+	texts := []string{"a", "b", "c", "d"}
+	ds := make([]*schema.ResourceData, len(texts))
+	for i, text := range texts {
+		ds[i] = resourceAviLicense().TestResourceData()
+		ds[i].Set("license_text", text)
+		wg.Add(1)
+		go func(d *schema.ResourceData) {
+			defer wg.Done()
+			if err := resourceAviLicenseCreate(d, client); err != nil {
+				t.Errorf("err: %s", err)
+			}
+		}(ds[i])
+	}
+	wg.Wait()
+	for i, text := range texts {
+		if ds[i].Id() != "lic-"+text {
+			t.Errorf("license %v tracks %v", text, ds[i].Id())
+		}
+	}
+}
+
+func TestResourceAviLicenseImportedDiff(t *testing.T) {
+	raw, err := config.NewRawConfig(map[string]interface{}{"license_text": "LICENSE"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	c := terraform.NewResourceConfig(raw)
+
+	// imported licenses have no license text in their state.
+	imported := &terraform.InstanceState{ID: "lic-1", Attributes: map[string]string{
+		"license_ids.#": "1", "license_ids.0": "lic-1"}}
+	diff, err := resourceAviLicense().Diff(imported, c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff != nil && (diff.Attributes["license_text"] != nil || diff.RequiresNew()) {
+		t.Errorf("expected no license text diff for imported licenses, got %v", diff)
+	}
+
+	applied := &terraform.InstanceState{ID: "lic-1", Attributes: map[string]string{
+		"license_text": "OLD", "license_ids.#": "1", "license_ids.0": "lic-1"}}
+	diff, err = resourceAviLicense().Diff(applied, c)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if diff == nil || diff.Attributes["license_text"] == nil || diff.RequiresNew() {
+		t.Errorf("expected changed license text to update in place, got %v", diff)
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"github.com/avinetworks/sdk/go/session"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ipReservationAttempts bounds how often a free address is picked again when
+// a parallel run reserved it first.
+var ipReservationAttempts = 10
+
+// ResourceNetworkIPReservationSchema is the schema of
+// avi_network_ip_reservation, which reserves an address of a network by
+// creating a string group named after it.
+func ResourceNetworkIPReservationSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"network_ref": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"ip_address": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		"subnet": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			ForceNew:      true,
+			ValidateFunc:  validateCIDROrIP,
+			ConflictsWith: []string{"ip_address"},
+		},
+		"description": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		"network_uuid": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+	}
+}
+
+func resourceAviNetworkIPReservation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAviNetworkIPReservationCreate,
+		Read:   ResourceAviNetworkIPReservationRead,
+		Delete: resourceAviNetworkIPReservationDelete,
+		Schema: ResourceNetworkIPReservationSchema(),
+	}
+}
+
+func resourceAviNetworkIPReservationCreate(d *schema.ResourceData, meta interface{}) error {
+	networkUUID, err := refFilterUUID(meta, "network_ref", d.Get("network_ref").(string), "network")
+	if err != nil {
+		return err
+	}
+	if ip, ok := d.GetOk("ip_address"); ok {
+		addr := net.ParseIP(ip.(string))
+		if addr == nil {
+			return fmt.Errorf("ip_address %q is not an IP address", ip)
+		}
+		uuid, err := reserveNetworkIP(meta, networkUUID, addr.String(), d.Get("description").(string))
+		if err != nil {
+			if isReservationConflict(err) {
+				return fmt.Errorf("%v is already reserved in network %v", addr, networkUUID)
+			}
+			return err
+		}
+		d.SetId(uuid)
+		return ResourceAviNetworkIPReservationRead(d, meta)
+	}
+	var subnet *net.IPNet
+	if s, ok := d.GetOk("subnet"); ok {
+		subnet = parseCIDROrIP(s.(string))
+	}
+	for i := 0; i < ipReservationAttempts; i++ {
+		ips, err := networkFreeIPs(meta, networkUUID, subnet, 1)
+		if err != nil {
+			return err
+		}
+		if len(ips) == 0 {
+			return fmt.Errorf("no address is free in the static ranges of network %v", networkUUID)
+		}
+		uuid, err := reserveNetworkIP(meta, networkUUID, ips[0], d.Get("description").(string))
+		if err == nil {
+			d.SetId(uuid)
+			return ResourceAviNetworkIPReservationRead(d, meta)
+		}
+		if !isReservationConflict(err) {
+			return err
+		}
+		log.Printf("[DEBUG] resourceAviNetworkIPReservationCreate %v was reserved by another run\n", ips[0])
+	}
+	return fmt.Errorf("could not reserve an address in network %v after %v attempts",
+		networkUUID, ipReservationAttempts)
+}
+
+// reserveNetworkIP creates the string group reserving ip in a network and
+// returns its uuid. It fails when the address is already reserved.
+func reserveNetworkIP(meta interface{}, networkUUID string, ip string, description string) (string, error) {
+	sg := map[string]interface{}{
+		"name":        ipReservationName(networkUUID, ip),
+		"type":        "SG_TYPE_STRING",
+		"description": description,
+		"kv":          []map[string]string{{"key": ip, "value": networkUUID}},
+	}
+	var res map[string]interface{}
+	path := "api/stringgroup"
+	if err := aviSession(meta).Post(path, sg, &res); err != nil {
+		log.Printf("[ERROR] reserveNetworkIP %v in POST of %v\n", err, path)
+		return "", err
+	}
+	uuid, _ := res["uuid"].(string)
+	return uuid, nil
+}
+
+// isReservationConflict reports whether creating a reservation failed
+// because a string group of the same name exists, which the controller
+// rejects with 409 Conflict.
+func isReservationConflict(err error) bool {
+	switch aviErr := err.(type) {
+	case session.AviError:
+		return aviErr.HttpStatusCode == http.StatusConflict
+	case *session.AviError:
+		return aviErr.HttpStatusCode == http.StatusConflict
+	}
+	return false
+}
+
+func ResourceAviNetworkIPReservationRead(d *schema.ResourceData, meta interface{}) error {
+	var sg struct {
+		Name string `json:"name"`
+		Kv   []struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		} `json:"kv"`
+	}
+	path := "api/stringgroup/" + d.Id()
+	if err := aviSession(meta).Get(path, &sg); err != nil {
+		if strings.Contains(err.Error(), "404") {
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] ResourceAviNetworkIPReservationRead %v in GET of %v\n", err, path)
+		return err
+	}
+	// the name makes the reservation unique, a string group that is not
+	// named after its address is not a reservation.
+	if len(sg.Kv) == 0 || sg.Name != ipReservationName(sg.Kv[0].Value, sg.Kv[0].Key) {
+		return fmt.Errorf("string group %v is not an address reservation", d.Id())
+	}
+	d.Set("ip_address", sg.Kv[0].Key)
+	d.Set("network_uuid", sg.Kv[0].Value)
+	return nil
+}
+
+func resourceAviNetworkIPReservationDelete(d *schema.ResourceData, meta interface{}) error {
+	path := "api/stringgroup/" + d.Id()
+	err := aviSession(meta).Delete(path)
+	if err != nil && !strings.Contains(err.Error(), "404") {
+		log.Printf("[ERROR] resourceAviNetworkIPReservationDelete %v in DELETE of %v\n", err, path)
+		return err
+	}
+	d.SetId("")
+	return nil
+}
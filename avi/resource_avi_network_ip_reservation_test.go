@@ -0,0 +1,103 @@
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceAviNetworkIPReservationLifecycle(t *testing.T) {
+	routes := testJSONHandler(testNetworkRoutes())
+	// reservations holds the string groups by name, 10.10.1.15 is reserved
+	// by another run after it was found free.
+	reservations := map[string]map[string]interface{}{
+		"terraform-ip-reservation-net-1-10.10.1.14": {"uuid": "stringgroup-1",
+			"kv": []interface{}{map[string]interface{}{"key": "10.10.1.14", "value": "net-1"}}},
+		"web-hosts": {"uuid": "stringgroup-web", "name": "web-hosts",
+			"kv": []interface{}{map[string]interface{}{"key": "10.10.1.20", "value": "net-1"}}},
+	}
+	raced := false
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/stringgroup" && r.Method == "POST":
+			var sg map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&sg)
+			name := sg["name"].(string)
+			if !raced && name == "terraform-ip-reservation-net-1-10.10.1.15" {
+				raced = true
+				reservations[name] = map[string]interface{}{"uuid": "stringgroup-other"}
+			}
+			if _, ok := reservations[name]; ok {
+				w.WriteHeader(http.StatusConflict)
+				fmt.Fprint(w, `{"error": "Duplicate name."}`)
+				return
+			}
+			sg["uuid"] = fmt.Sprintf("stringgroup-%d", len(reservations)+2)
+			reservations[name] = sg
+			json.NewEncoder(w).Encode(sg)
+		case r.URL.Path == "/api/stringgroup" && r.Method == "GET":
+			results := []interface{}{}
+			for name, sg := range reservations {
+				results = append(results, map[string]interface{}{"uuid": sg["uuid"], "name": name})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "results": results})
+		case strings.HasPrefix(r.URL.Path, "/api/stringgroup/"):
+			uuid := strings.TrimPrefix(r.URL.Path, "/api/stringgroup/")
+			for name, sg := range reservations {
+				if sg["uuid"] != uuid {
+					continue
+				}
+				if r.Method == "DELETE" {
+					delete(reservations, name)
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+				json.NewEncoder(w).Encode(sg)
+				return
+			}
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			routes(w, r)
+		}
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviNetworkIPReservation().TestResourceData()
+	d.Set("network_ref", "net-1")
+	if err := resourceAviNetworkIPReservationCreate(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	expected := map[string]interface{}{
+		"ip_address":   "10.10.1.16",
+		"network_uuid": "net-1",
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%v = %v, expected %v", k, actual, v)
+		}
+	}
+
+	d2 := resourceAviNetworkIPReservation().TestResourceData()
+	d2.Set("network_ref", "net-1")
+	d2.Set("ip_address", "10.10.1.16")
+	if err := resourceAviNetworkIPReservationCreate(d2, client); err == nil || !strings.Contains(err.Error(), "already reserved") {
+		t.Errorf("err = %v, expected 10.10.1.16 to be reserved", err)
+	}
+
+	// a string group that is not named after its address is not a reservation.
+	d3 := resourceAviNetworkIPReservation().TestResourceData()
+	d3.SetId("stringgroup-web")
+	if err := ResourceAviNetworkIPReservationRead(d3, client); err == nil || !strings.Contains(err.Error(), "not an address reservation") {
+		t.Errorf("err = %v, expected stringgroup-web not to be a reservation", err)
+	}
+
+	if err := resourceAviNetworkIPReservationDelete(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ResourceAviNetworkIPReservationRead(d, client); err != nil || d.Id() != "" {
+		t.Errorf("id = %q, err = %v, expected the reservation to be gone", d.Id(), err)
+	}
+}
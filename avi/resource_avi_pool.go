@@ -7,7 +7,6 @@ package avi
 
 import (
 	"errors"
-	"github.com/avinetworks/sdk/go/clients"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strings"
@@ -323,7 +322,8 @@ func ResourcePoolImporter(d *schema.ResourceData, m interface{}) ([]*schema.Reso
 
 func ResourceAviPoolRead(d *schema.ResourceData, meta interface{}) error {
 	s := ResourcePoolSchema()
-	ignoreServers := d.Get("ignore_servers").(bool)
+	// the avi_pool data source has no ignore_servers.
+	ignoreServers, _ := d.Get("ignore_servers").(bool)
 	err := ApiRead(d, meta, "pool", s)
 	if err != nil {
 		log.Printf("[ERROR] in reading object %v\n", err)
@@ -400,11 +400,9 @@ func resourceAviPoolDelete(d *schema.ResourceData, meta interface{}) error {
 	if ApiDeleteSystemDefaultCheck(d) {
 		return nil
 	}
-	client := meta.(*clients.AviClient)
 	uuid := d.Get("uuid").(string)
 	if uuid != "" {
-		path := "api/" + objType + "/" + uuid
-		err := client.AviSession.Delete(path)
+		err := ApiDelete(meta, objType, uuid)
 		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
 			log.Println("[ERROR] resourceAviPoolDelete not found")
 			return err
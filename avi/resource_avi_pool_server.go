@@ -6,11 +6,14 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
+	"encoding/json"
+	"fmt"
 	"github.com/avinetworks/sdk/go/models"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 func ResourceAviPoolServerSchema() map[string]*schema.Schema {
@@ -40,11 +43,23 @@ func ResourceAviPoolServerSchema() map[string]*schema.Schema {
 			Type:     schema.TypeString,
 			Optional: true,
 		},
+		"discovered_network_ref": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
 		"discovered_networks": {
 			Type:     schema.TypeList,
 			Optional: true,
 			Elem:     ResourceDiscoveredNetworkSchema(),
 		},
+		"discovered_subnet": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Computed: true,
+			Elem:     ResourceIpAddrPrefixSchema(),
+		},
 		"enabled": {
 			Type:     schema.TypeBool,
 			Optional: true,
@@ -128,17 +143,65 @@ func resourceAviServer() *schema.Resource {
 		Update: resourceAviServerCreateOrUpdate,
 		Delete: resourceAviServerDelete,
 		Schema: ResourceAviPoolServerSchema(),
+		Importer: &schema.ResourceImporter{
+			State: ResourceAviServerImporter,
+		},
 	}
 }
 
+// ResourceAviServerImporter imports a pool member using the same
+// pool_uuid:ip:port ID that ResourceAviServerRead records. IPv6 addresses may
+// contain colons so the pool uuid and port are taken from the two ends.
+func ResourceAviServerImporter(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := d.Id()
+	first := strings.Index(id, ":")
+	last := strings.LastIndex(id, ":")
+	if first <= 0 || last <= first+1 || last == len(id)-1 {
+		return nil, fmt.Errorf("invalid avi_server ID %q, expected pool_uuid:ip:port", id)
+	}
+	pUUID := id[:first]
+	ip := id[first+1 : last]
+	port, err := strconv.Atoi(id[last+1:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid port in avi_server ID %q: %v", id, err)
+	}
+	d.Set("pool_ref", pUUID)
+	d.Set("ip", ip)
+	if port != 0 {
+		d.Set("port", port)
+	}
+	err, _, poolObj, pserver := resourceAviServerReadApi(d, meta)
+	if err != nil {
+		return nil, err
+	}
+	if pserver == nil {
+		return nil, fmt.Errorf("server %v port %v not found in pool %v", ip, port, pUUID)
+	}
+	if poolObj.URL != nil {
+		d.Set("pool_ref", *poolObj.URL)
+	}
+	if err := ResourceAviServerRead(d, meta); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
 func resourceAviServerCreateOrUpdate(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AviClient)
-	err, pUUID, poolObj, pserver := resourceAviServerReadApi(d, meta)
-	//added check for err and poolObj.
-	if err != nil || poolObj == nil {
-		log.Printf("[ERROR] resourceAviServerCreateOrUpdate Error during fetching pool object using pool_ref %v", err)
+	pUUID := UUIDFromID(d.Get("pool_ref").(string))
+	err := poolServerBatches.add(meta, pUUID, func(poolObj *models.Pool) (*models.Server, error) {
+		return resourceAviServerFromData(d, poolObj)
+	})
+	if err != nil {
+		log.Printf("[ERROR] resourceAviServerCreateOrUpdate pool %v server %v err %v", pUUID, d.Get("ip"), err)
 		return err
 	}
+	return ResourceAviServerRead(d, meta)
+}
+
+// resourceAviServerFromData returns the server of poolObj that d manages with
+// the attributes of d applied, or a new server when the pool does not have it.
+func resourceAviServerFromData(d *schema.ResourceData, poolObj *models.Pool) (*models.Server, error) {
+	pserver := findPoolServer(poolObj, d.Get("ip").(string), int32(d.Get("port").(int)))
 	if pserver == nil {
 		// not found
 		newServer := models.Server{}
@@ -148,7 +211,7 @@ func resourceAviServerCreateOrUpdate(d *schema.ResourceData, meta interface{}) e
 		}
 		pserver = &newServer
 	}
-	log.Printf("[INFO] resourceAviServerCreateOrUpdate pool %v server %v", pUUID, pserver)
+	log.Printf("[INFO] resourceAviServerFromData pool %v server %v", poolObj.UUID, pserver)
 	//set other attributes from server.
 	if hostname, ok := d.GetOk("hostname"); ok {
 		hostnameStr := hostname.(string)
@@ -166,8 +229,26 @@ func resourceAviServerCreateOrUpdate(d *schema.ResourceData, meta interface{}) e
 		desc := Description.(string)
 		pserver.Description = &desc
 	}
+	if DiscoveredNetworkRef, ok := d.GetOk("discovered_network_ref"); ok {
+		var refs []string
+		for _, ref := range DiscoveredNetworkRef.([]interface{}) {
+			refs = append(refs, ref.(string))
+		}
+		pserver.DiscoveredNetworkRef = refs
+	}
 	if DiscoveredNetworks, ok := d.GetOk("discovered_networks"); ok {
-		pserver.DiscoveredNetworks = DiscoveredNetworks.([]*models.DiscoveredNetwork)
+		var networks []*models.DiscoveredNetwork
+		if err := schemaToModel(DiscoveredNetworks, &networks); err != nil {
+			return nil, err
+		}
+		pserver.DiscoveredNetworks = networks
+	}
+	if DiscoveredSubnet, ok := d.GetOk("discovered_subnet"); ok {
+		var subnets []*models.IPAddrPrefix
+		if err := schemaToModel(DiscoveredSubnet, &subnets); err != nil {
+			return nil, err
+		}
+		pserver.DiscoveredSubnet = subnets
 	}
 	if Enabled, ok := d.GetOk("enabled"); ok {
 		en := Enabled.(bool)
@@ -182,7 +263,11 @@ func resourceAviServerCreateOrUpdate(d *schema.ResourceData, meta interface{}) e
 		pserver.ExternalUUID = &extUUID
 	}
 	if Location, ok := d.GetOk("location"); ok {
-		pserver.Location = Location.(*models.GeoLocation)
+		var location models.GeoLocation
+		if err := schemaToModel(Location, &location); err != nil {
+			return nil, err
+		}
+		pserver.Location = &location
 	}
 	if MacAddress, ok := d.GetOk("mac_address"); ok {
 		mac := MacAddress.(string)
@@ -230,20 +315,7 @@ func resourceAviServerCreateOrUpdate(d *schema.ResourceData, meta interface{}) e
 		pserver.IP = &models.IPAddr{Type: &tStr, Addr: &ip}
 	}
 
-	uri := "api/pool/" + pUUID
-	var response interface{}
-	patchPool := models.Pool{}
-	patchPool.Name = poolObj.Name
-	patchPool.TenantRef = poolObj.TenantRef
-	patchPool.CloudRef = poolObj.CloudRef
-	patchPool.Servers = append(patchPool.Servers, pserver)
-	err = client.AviSession.Patch(uri, patchPool, "add", response)
-	log.Printf("[INFO] resourceAviServerCreateOrUpdate pool %v poolobj %v err %v response %v",
-		pUUID, patchPool, err, response)
-	if err == nil {
-		err = ResourceAviServerRead(d, meta)
-	}
-	return err
+	return pserver, nil
 }
 
 func ResourceAviServerRead(d *schema.ResourceData, meta interface{}) error {
@@ -260,88 +332,84 @@ func ResourceAviServerRead(d *schema.ResourceData, meta interface{}) error {
 		log.Printf("[INFO] pool %v ip %v port %v", pUUID, ip, portStr)
 		d.SetId(sUUID)
 		// Fill in the server information
-		if pserver.Hostname != nil {
-			d.Set("hostname", pserver.Hostname)
-		}
-
-		d.Set("enabled", pserver.Enabled)
-
-		if pserver.AutoscalingGroupName != nil {
-			d.Set("autoscaling_group_name", pserver.AutoscalingGroupName)
-		}
-		if pserver.AvailabilityZone != nil {
-			d.Set("availability_zone", pserver.AvailabilityZone)
-		}
-		if pserver.Description != nil {
-			d.Set("description", pserver.Description)
-		}
-		if pserver.DiscoveredNetworks != nil {
-			d.Set("discovered_networks", pserver.DiscoveredNetworks)
-		}
-
-		if pserver.ExternalUUID != nil {
-			d.Set("external_uuid", pserver.ExternalUUID)
-		}
-		if pserver.ExternalOrchestrationID != nil {
-			d.Set("external_orchestration_id", pserver.ExternalOrchestrationID)
-		}
+		err = resourceAviServerSetData(d, pserver)
+	}
+	return err
+}
 
-		if pserver.Location != nil {
-			d.Set("mac_address", pserver.Location)
-		}
-		if pserver.NwRef != nil {
-			d.Set("nw_ref", pserver.NwRef)
-		}
-		if pserver.PrstHdrVal != nil {
-			d.Set("prst_hdr_val", pserver.PrstHdrVal)
-		}
-		d.Set("ratio", pserver.Ratio)
-		d.Set("resolve_server_by_dns", pserver.ResolveServerByDNS)
-		d.Set("rewrite_host_header", pserver.RewriteHostHeader)
-		if pserver.ServerNode != nil {
-			d.Set("server_node", pserver.ServerNode)
-		}
-		d.Set("static", pserver.Static)
-		d.Set("verify_network", pserver.VerifyNetwork)
-		if pserver.VMRef != nil {
-			d.Set("vm_ref", pserver.VMRef)
-		}
-		// Add more fields to read.
+// resourceAviServerSetData copies every field of the pool member into d. The
+// server ip is flattened into the ip and type attributes of the resource.
+func resourceAviServerSetData(d *schema.ResourceData, pserver *models.Server) error {
+	data, err := json.Marshal(pserver)
+	if err != nil {
+		return err
+	}
+	var sobj map[string]interface{}
+	if err := json.Unmarshal(data, &sobj); err != nil {
+		return err
+	}
+	if ip, ok := sobj["ip"].(map[string]interface{}); ok {
+		d.Set("ip", ip["addr"])
+		d.Set("type", ip["type"])
 	}
+	delete(sobj, "ip")
+	_, err = ApiDataToSchema(sobj, d, ResourceAviPoolServerSchema())
 	return err
 }
 
 func resourceAviServerReadApi(d *schema.ResourceData, meta interface{}) (error, string, *models.Pool, *models.Server) {
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	pUUID := UUIDFromID(d.Get("pool_ref").(string))
 	uri := "api/pool/" + pUUID
 	var poolObj *models.Pool
-	err := client.AviSession.Get(uri, &poolObj)
+	err := sess.Get(uri, &poolObj)
 	if err != nil {
 		log.Printf("[ERROR] pool uuid %v not found", pUUID)
 		return err, pUUID, nil, nil
 	}
 	log.Printf("[INFO] found pool %v", poolObj.Name)
-	ip := d.Get("ip").(string)
-	port := d.Get("port")
+	matchedServer := findPoolServer(poolObj, d.Get("ip").(string), int32(d.Get("port").(int)))
+	return nil, pUUID, poolObj, matchedServer
+}
 
-	// find the server in the pool object.
-	var matchedServer *models.Server = nil
+// findPoolServer returns the server of poolObj with the given ip and port.
+// Servers without a port use the pool default and are matched when no port is
+// configured.
+func findPoolServer(poolObj *models.Pool, ip string, port int32) *models.Server {
 	for i := 0; i < len(poolObj.Servers); i++ {
 		sObj := poolObj.Servers[i]
-		if *sObj.IP.Addr == ip {
-			if (port == nil && *sObj.Port == 0) || (port != nil && int32(port.(int)) == *sObj.Port) {
-				matchedServer = sObj
-				break
-			}
+		if sObj.IP == nil || sObj.IP.Addr == nil || *sObj.IP.Addr != ip {
+			continue
+		}
+		var sPort int32
+		if sObj.Port != nil {
+			sPort = *sObj.Port
+		}
+		if sPort == port {
+			return sObj
 		}
 	}
-	return nil, pUUID, poolObj, matchedServer
+	return nil
 }
 
 func resourceAviServerDelete(d *schema.ResourceData, meta interface{}) error {
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
+	pUUID := UUIDFromID(d.Get("pool_ref").(string))
+	// the pool is read and patched as a whole, other servers of the pool
+	// must not be added or deleted in between.
+	objectLocks.Lock(pUUID)
+	defer objectLocks.Unlock(pUUID)
 	err, pUUID, poolObj, pserver := resourceAviServerReadApi(d, meta)
+	if err != nil {
+		// the server was deleted with its pool.
+		if strings.Contains(err.Error(), "404") {
+			log.Printf("[INFO] resourceAviServerDelete pool %v of server %v not found", pUUID, d.Id())
+			d.SetId("")
+			return nil
+		}
+		log.Printf("[ERROR] resourceAviServerDelete pool %v server %v err %v", pUUID, d.Id(), err)
+		return err
+	}
 	log.Printf("[DEBUG] pool %v %v server %v", pUUID, poolObj.Name, d.Id())
 	if pserver != nil {
 		uri := "api/pool/" + pUUID
@@ -350,9 +418,102 @@ func resourceAviServerDelete(d *schema.ResourceData, meta interface{}) error {
 		var servers = make([]models.Server, 1)
 		servers[0] = *pserver
 		patchPool["servers"] = servers
-		err = client.AviSession.Patch(uri, patchPool, "delete", response)
+		err = sess.Patch(uri, patchPool, "delete", response)
+		invalidateObjectCache(meta, "pool", pUUID)
 		log.Printf("[INFO] pool %v server %v deleted err %v", patchPool, d.Id(), err)
 	}
 	d.SetId("")
 	return err
 }
+
+// poolServerBatch is a set of servers added to one pool with a single PATCH.
+type poolServerBatch struct {
+	servers []*poolServerBatchEntry
+	done    chan struct{}
+}
+
+// poolServerBatchEntry builds one server of a batch from the pool read by
+// the batch. err is set once the batch is done.
+type poolServerBatchEntry struct {
+	build func(poolObj *models.Pool) (*models.Server, error)
+	err   error
+}
+
+// poolServerBatcher groups avi_server creates and updates on the same pool.
+// The first server of a batch waits for the pool lock and, once it has it,
+// closes the batch and writes every server that joined it in one read of the
+// pool and one PATCH. Servers that arrive while a batch is written start the
+// next batch, so concurrent applies to one pool need few PATCHes and never
+// overwrite each other.
+type poolServerBatcher struct {
+	lock    sync.Mutex
+	pending map[string]*poolServerBatch
+}
+
+var poolServerBatches = &poolServerBatcher{pending: map[string]*poolServerBatch{}}
+
+// add queues the server returned by build for the pool with pUUID and waits
+// until it has been written.
+func (b *poolServerBatcher) add(meta interface{}, pUUID string,
+	build func(poolObj *models.Pool) (*models.Server, error)) error {
+	entry := &poolServerBatchEntry{build: build}
+	b.lock.Lock()
+	if batch, ok := b.pending[pUUID]; ok {
+		batch.servers = append(batch.servers, entry)
+		b.lock.Unlock()
+		<-batch.done
+		return entry.err
+	}
+	batch := &poolServerBatch{servers: []*poolServerBatchEntry{entry}, done: make(chan struct{})}
+	b.pending[pUUID] = batch
+	b.lock.Unlock()
+
+	objectLocks.Lock(pUUID)
+	defer objectLocks.Unlock(pUUID)
+	b.lock.Lock()
+	delete(b.pending, pUUID)
+	b.lock.Unlock()
+	b.write(meta, pUUID, batch)
+	close(batch.done)
+	return entry.err
+}
+
+// write reads the pool once and adds every server of batch with one PATCH.
+func (b *poolServerBatcher) write(meta interface{}, pUUID string, batch *poolServerBatch) {
+	sess := aviSession(meta)
+	uri := "api/pool/" + pUUID
+	var poolObj *models.Pool
+	err := sess.Get(uri, &poolObj)
+	if err != nil {
+		log.Printf("[ERROR] poolServerBatcher pool uuid %v not found", pUUID)
+		for _, entry := range batch.servers {
+			entry.err = err
+		}
+		return
+	}
+	var response interface{}
+	patchPool := models.Pool{}
+	patchPool.Name = poolObj.Name
+	patchPool.TenantRef = poolObj.TenantRef
+	patchPool.CloudRef = poolObj.CloudRef
+	var added []*poolServerBatchEntry
+	for _, entry := range batch.servers {
+		pserver, err := entry.build(poolObj)
+		if err != nil {
+			entry.err = err
+			continue
+		}
+		patchPool.Servers = append(patchPool.Servers, pserver)
+		added = append(added, entry)
+	}
+	if len(added) == 0 {
+		return
+	}
+	err = sess.Patch(uri, patchPool, "add", response)
+	invalidateObjectCache(meta, "pool", pUUID)
+	log.Printf("[INFO] poolServerBatcher pool %v added %v servers err %v response %v",
+		pUUID, len(added), err, response)
+	for _, entry := range added {
+		entry.err = err
+	}
+}
@@ -0,0 +1,77 @@
+package avi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceAviServerImporter(t *testing.T) {
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/pool/pool-1" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"uuid": "pool-1",
+			"name": "web",
+			"url":  "https://localhost/api/pool/pool-1",
+			"servers": []map[string]interface{}{
+				{"ip": map[string]string{"addr": "10.10.0.1", "type": "V4"}, "port": 8080, "hostname": "web-1"},
+				{"ip": map[string]string{"addr": "10.10.0.2", "type": "V4"}},
+				{"ip": map[string]string{"addr": "2001:db8::1", "type": "V6"}, "port": 80},
+			},
+		})
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	cases := []struct {
+		id   string
+		ip   string
+		port int
+		typ  string
+	}{
+		{"pool-1:10.10.0.1:8080", "10.10.0.1", 8080, "V4"},
+		{"pool-1:10.10.0.2:0", "10.10.0.2", 0, "V4"},
+		{"pool-1:2001:db8::1:80", "2001:db8::1", 80, "V6"},
+	}
+	for _, c := range cases {
+		d := resourceAviServer().TestResourceData()
+		d.SetId(c.id)
+		results, err := ResourceAviServerImporter(d, client)
+		if err != nil {
+			t.Errorf("%v: err: %s", c.id, err)
+			continue
+		}
+		if len(results) != 1 || d.Id() != c.id {
+			t.Errorf("%v: imported %v with id %v", c.id, results, d.Id())
+		}
+		if d.Get("ip") != c.ip || d.Get("port") != c.port || d.Get("type") != c.typ {
+			t.Errorf("%v: imported ip %v port %v type %v", c.id, d.Get("ip"), d.Get("port"), d.Get("type"))
+		}
+		if d.Get("pool_ref") != "https://localhost/api/pool/pool-1" {
+			t.Errorf("%v: imported pool_ref %v", c.id, d.Get("pool_ref"))
+		}
+	}
+
+	errors := []struct {
+		id  string
+		err string
+	}{
+		{"pool-1:10.10.0.1", "expected pool_uuid:ip:port"},
+		{"pool-1:10.10.0.1:", "expected pool_uuid:ip:port"},
+		{":10.10.0.1:80", "expected pool_uuid:ip:port"},
+		{"pool-1:10.10.0.1:http", "invalid port"},
+		{"pool-1:10.10.0.3:80", "server 10.10.0.3 port 80 not found in pool pool-1"},
+		{"pool-1:10.10.0.1:80", "server 10.10.0.1 port 80 not found in pool pool-1"},
+	}
+	for _, c := range errors {
+		d := resourceAviServer().TestResourceData()
+		d.SetId(c.id)
+		if _, err := ResourceAviServerImporter(d, client); err == nil || !strings.Contains(err.Error(), c.err) {
+			t.Errorf("%v: err = %v, expected %q", c.id, err, c.err)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/hashicorp/terraform/helper/schema"
+	"log"
+	"net/url"
+	"strings"
+)
+
+// ResourceRestObjectSchema is the schema of avi_rest_object, which manages an
+// object of any type through its JSON representation. It covers object types
+// that have no typed resource in the provider.
+func ResourceRestObjectSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"object_type": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"tenant": {
+			Type:     schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		"body": {
+			Type:             schema.TypeString,
+			Required:         true,
+			ValidateFunc:     validateRestObjectBody,
+			DiffSuppressFunc: suppressJSONSubsetDiff,
+		},
+		"uuid": {
+			Type:     schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+	}
+}
+
+func resourceAviRestObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAviRestObjectCreate,
+		Read:   ResourceAviRestObjectRead,
+		Update: resourceAviRestObjectUpdate,
+		Delete: resourceAviRestObjectDelete,
+		Schema: ResourceRestObjectSchema(),
+		Importer: &schema.ResourceImporter{
+			State: ResourceRestObjectImporter,
+		},
+	}
+}
+
+// ResourceRestObjectImporter imports an object by <object_type>/<uuid>, for
+// example alertobjectlist/alertobjectlist-5f1c, or by
+// <tenant>/<object_type>/<uuid> for an object of another tenant than the one
+// of the provider. The whole object is imported as body.
+func ResourceRestObjectImporter(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	id := strings.TrimPrefix(strings.TrimPrefix(d.Id(), "/"), "api/")
+	parts := strings.Split(id, "/")
+	if len(parts) == 3 && parts[0] != "" {
+		d.Set("tenant", parts[0])
+		parts = parts[1:]
+	}
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid avi_rest_object id %v, expected [<tenant>/]<object_type>/<uuid>", d.Id())
+	}
+	d.Set("object_type", parts[0])
+	d.Set("uuid", parts[1])
+	d.SetId(parts[1])
+	if err := ResourceAviRestObjectRead(d, meta); err != nil {
+		return nil, err
+	}
+	if d.Id() == "" {
+		return nil, fmt.Errorf("%v object %v not found", parts[0], parts[1])
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// restObjectMeta returns meta with its API calls sent in the tenant of the
+// object of d.
+func restObjectMeta(d *schema.ResourceData, meta interface{}) interface{} {
+	return withTenant(meta, d.Get("tenant").(string))
+}
+
+func ResourceAviRestObjectRead(d *schema.ResourceData, meta interface{}) error {
+	objType := d.Get("object_type").(string)
+	obj := apiReadObject(d, restObjectMeta(d, meta), objType)
+	if obj == nil {
+		return nil
+	}
+	SetIDFromObj(d, obj)
+	// only the keys of the configured body are kept so that defaults filled
+	// in by the controller do not show up as changes. An imported object has
+	// no body yet and keeps all of its keys.
+	state := obj
+	if body := d.Get("body").(string); body != "" {
+		var wanted interface{}
+		if err := json.Unmarshal([]byte(body), &wanted); err == nil {
+			state = jsonSubset(obj, wanted)
+		}
+	}
+	stateBody, err := json.Marshal(state)
+	if err != nil {
+		log.Printf("[ERROR] ResourceAviRestObjectRead %v in encoding %v\n", err, state)
+		return err
+	}
+	d.Set("body", string(stateBody))
+	return nil
+}
+
+func resourceAviRestObjectCreate(d *schema.ResourceData, meta interface{}) error {
+	err := resourceAviRestObjectWrite(d, meta)
+	if err == nil {
+		err = ResourceAviRestObjectRead(d, meta)
+	}
+	return err
+}
+
+func resourceAviRestObjectUpdate(d *schema.ResourceData, meta interface{}) error {
+	err := resourceAviRestObjectWrite(d, meta)
+	if err == nil {
+		err = ResourceAviRestObjectRead(d, meta)
+	}
+	return err
+}
+
+// resourceAviRestObjectWrite creates or updates the object from body in the
+// tenant of d. The tenant is also passed as tenant_ref unless body sets one.
+func resourceAviRestObjectWrite(d *schema.ResourceData, meta interface{}) error {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(d.Get("body").(string)), &data); err != nil {
+		return fmt.Errorf("invalid body: %v", err)
+	}
+	if tenant, ok := d.GetOk("tenant"); ok {
+		if _, ok := data["tenant_ref"]; !ok {
+			data["tenant_ref"] = "/api/tenant/?name=" + url.QueryEscape(tenant.(string))
+		}
+	}
+	return apiCreateOrUpdateData(d, restObjectMeta(d, meta), d.Get("object_type").(string), data, false)
+}
+
+func resourceAviRestObjectDelete(d *schema.ResourceData, meta interface{}) error {
+	objType := d.Get("object_type").(string)
+	uuid := d.Get("uuid").(string)
+	if uuid != "" {
+		err := ApiDelete(restObjectMeta(d, meta), objType, uuid)
+		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
+			log.Println("[INFO] resourceAviRestObjectDelete not found")
+			return err
+		}
+		d.SetId("")
+	}
+	return nil
+}
+
+func validateRestObjectBody(v interface{}, k string) (ws []string, es []error) {
+	var body map[string]interface{}
+	if err := json.Unmarshal([]byte(v.(string)), &body); err != nil {
+		es = append(es, fmt.Errorf("%q must be a JSON object: %v", k, err))
+	}
+	return
+}
@@ -0,0 +1,241 @@
+package avi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/avinetworks/sdk/go/clients"
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestAVIRestObjectBasic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckAVIRestObjectDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccAVIRestObjectConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAVIRestObjectExists("avi_rest_object.testRestObject"),
+					resource.TestCheckResourceAttr(
+						"avi_rest_object.testRestObject", "object_type", "stringgroup")),
+			},
+			{
+				Config: testAccUpdatedAVIRestObjectConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckAVIRestObjectExists("avi_rest_object.testRestObject"),
+					resource.TestCheckResourceAttr(
+						"avi_rest_object.testRestObject", "body",
+						`{"kv":[{"key":"test-abc"}],"name":"test-rest-object","type":"SG_TYPE_STRING"}`)),
+			},
+		},
+	})
+}
+
+func testAccCheckAVIRestObjectExists(resourcename string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		conn := testAccProvider.Meta().(*clients.AviClient).AviSession
+		var obj interface{}
+		rs, ok := s.RootModule().Resources[resourcename]
+		if !ok {
+			return fmt.Errorf("Not found: %s", resourcename)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No AVI RestObject ID is set")
+		}
+		url := strings.SplitN(rs.Primary.ID, "/api", 2)[1]
+		uuid := strings.Split(url, "#")[0]
+		path := "api" + uuid
+		err := conn.Get(path, &obj)
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+}
+
+func testAccCheckAVIRestObjectDestroy(s *terraform.State) error {
+	conn := testAccProvider.Meta().(*clients.AviClient).AviSession
+	var obj interface{}
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "avi_rest_object" {
+			continue
+		}
+		url := strings.SplitN(rs.Primary.ID, "/api", 2)[1]
+		uuid := strings.Split(url, "#")[0]
+		path := "api" + uuid
+		err := conn.Get(path, &obj)
+		if err != nil {
+			if strings.Contains(err.Error(), "404") {
+				return nil
+			}
+			return err
+		}
+		if len(obj.(map[string]interface{})) > 0 {
+			return fmt.Errorf("AVI RestObject still exists")
+		}
+	}
+	return nil
+}
+
+const testAccAVIRestObjectConfig = `
+resource "avi_rest_object" "testRestObject" {
+	object_type = "stringgroup"
+	tenant = "admin"
+	body = <<EOF
+{"name": "test-rest-object", "type": "SG_TYPE_STRING", "kv": [{"key": "test"}]}
+EOF
+}
+`
+
+const testAccUpdatedAVIRestObjectConfig = `
+resource "avi_rest_object" "testRestObject" {
+	object_type = "stringgroup"
+	tenant = "admin"
+	body = <<EOF
+{"name": "test-rest-object", "type": "SG_TYPE_STRING", "kv": [{"key": "test-abc"}]}
+EOF
+}
+`
+
+func TestRestObjectBodyDiff(t *testing.T) {
+	var server interface{}
+	json.Unmarshal([]byte(`{"uuid": "sg-1", "name": "sg", "type": "SG_TYPE_STRING", "longest_match": true,
+		"tenant_ref": "https://10.10.10.10/api/tenant/admin#admin",
+		"kv": [{"key": "a", "value": ""}]}`), &server)
+	// old is the previous body, the stored body is the object read for it.
+	cases := []struct {
+		old      string
+		new      string
+		suppress bool
+	}{
+		{`{"name": "sg", "kv": [{"key": "a"}]}`, `{"kv":[{"key":"a"}],   "name":"sg"}`, true},
+		{`{"name": "sg", "tenant_ref": "/api/tenant/?name=admin"}`, `{"name": "sg", "tenant_ref": "/api/tenant/?name=admin"}`, true},
+		{`{"name": "sg", "tenant_ref": "/api/tenant/admin"}`, `{"name": "sg", "tenant_ref": "/api/tenant/?name=other"}`, false},
+		{`{"name": "sg", "longest_match": true}`, `{"name": "sg", "longest_match": false}`, false},
+		{`{"name": "sg", "kv": [{"key": "a"}]}`, `{"name": "sg", "kv": [{"key": "a"}, {"key": "b"}]}`, false},
+		{`{"name": "sg"}`, `{"name": "sg", "description": "new"}`, false},
+		// removed keys are changes.
+		{`{"name": "sg", "longest_match": true}`, `{"name": "sg"}`, false},
+		{`{"name": "sg", "kv": [{"key": "a", "value": ""}]}`, `{"name": "sg", "kv": [{"key": "a"}]}`, false},
+	}
+	for _, c := range cases {
+		var wanted interface{}
+		json.Unmarshal([]byte(c.old), &wanted)
+		stored, _ := json.Marshal(jsonSubset(server, wanted))
+		if suppress := suppressJSONSubsetDiff("body", string(stored), c.new, nil); suppress != c.suppress {
+			t.Errorf("suppressJSONSubsetDiff(%v, %v) = %v, expected %v", c.old, c.new, suppress, c.suppress)
+		}
+	}
+}
+
+func TestRestObjectCreateAndRead(t *testing.T) {
+	var posted map[string]interface{}
+	var sentTenantRef interface{}
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/microservice":
+			fmt.Fprint(w, `{"count": 0, "results": []}`)
+		case r.Method == "POST" && r.URL.Path == "/api/microservice":
+			if err := json.NewDecoder(r.Body).Decode(&posted); err != nil {
+				t.Errorf("err: %s", err)
+			}
+			sentTenantRef = posted["tenant_ref"]
+			posted["uuid"] = "microservice-1"
+			posted["url"] = "https://" + r.Host + "/api/microservice/microservice-1"
+			posted["tenant_ref"] = "https://" + r.Host + "/api/tenant/tenant-1#t1"
+			posted["created_by"] = "admin"
+			json.NewEncoder(w).Encode(posted)
+		case r.Method == "GET" && r.URL.Path == "/api/microservice/microservice-1":
+			json.NewEncoder(w).Encode(posted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviRestObject().TestResourceData()
+	d.Set("object_type", "microservice")
+	d.Set("tenant", "t1")
+	d.Set("body", `{"name": "ms", "application_name": "app"}`)
+	if err := resourceAviRestObjectCreate(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if sentTenantRef != "/api/tenant/?name=t1" {
+		t.Fatalf("unexpected tenant_ref %v", sentTenantRef)
+	}
+	if uuid := d.Get("uuid"); uuid != "microservice-1" {
+		t.Fatalf("unexpected uuid %v", uuid)
+	}
+	if body := d.Get("body"); body != `{"application_name":"app","name":"ms"}` {
+		t.Fatalf("unexpected body %v", body)
+	}
+}
+
+func TestResourceAviRestObjectTenant(t *testing.T) {
+	var tenants []string
+	exists := false
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		tenant := r.Header.Get("X-Avi-Tenant")
+		tenants = append(tenants, r.Method+" "+tenant)
+		obj := map[string]interface{}{"uuid": "stringgroup-1", "name": "team-strings",
+			"url": "https://localhost/api/stringgroup/stringgroup-1", "type": "SG_TYPE_STRING"}
+		// the object is only visible in its tenant.
+		visible := exists && tenant == "team-a"
+		switch {
+		case r.URL.Path == "/api/stringgroup" && r.Method == "GET":
+			results := []interface{}{}
+			if visible {
+				results = append(results, obj)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "results": results})
+		case r.URL.Path == "/api/stringgroup" && r.Method == "POST":
+			exists = true
+			json.NewEncoder(w).Encode(obj)
+		case r.URL.Path == "/api/stringgroup/stringgroup-1" && visible:
+			if r.Method == "DELETE" {
+				exists = false
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			json.NewEncoder(w).Encode(obj)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviRestObject().TestResourceData()
+	d.Set("object_type", "stringgroup")
+	d.Set("tenant", "team-a")
+	d.Set("body", `{"name": "team-strings", "type": "SG_TYPE_STRING"}`)
+	if err := resourceAviRestObjectCreate(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := ResourceAviRestObjectRead(d, client); err != nil || d.Id() == "" {
+		t.Fatalf("id = %q, err = %v, expected the object to be read in its tenant", d.Id(), err)
+	}
+	if err := resourceAviRestObjectDelete(d, client); err != nil || exists {
+		t.Fatalf("exists = %v, err = %v, expected the object to be deleted in its tenant", exists, err)
+	}
+	for _, request := range tenants {
+		if !strings.HasSuffix(request, " team-a") {
+			t.Errorf("%v, expected every request in tenant team-a", request)
+		}
+	}
+
+	// requests of other resources stay in the tenant of the provider.
+	tenants = nil
+	var res interface{}
+	aviSession(client).Get("api/stringgroup", &res)
+	if len(tenants) != 1 || tenants[0] != "GET admin" {
+		t.Errorf("requests = %v, expected a GET in tenant admin", tenants)
+	}
+}
@@ -6,8 +6,9 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/schema"
+	"log"
 	"strings"
 )
 
@@ -51,7 +52,41 @@ func resourceAviUserAccount() *schema.Resource {
 		Update: resourceAviUserAccountUpdate,
 		Delete: resourceAviUserAccountDelete,
 		Schema: ResourceUserAccountSchema(),
+		Importer: &schema.ResourceImporter{
+			State: ResourceAviUserAccountImporter,
+		},
+	}
+}
+
+// ResourceAviUserAccountImporter imports the account the provider is logged in
+// with. The import ID is the username; api/useraccount only serves the current
+// user so any other username is rejected. The account details are only set
+// here, as Read does not refresh them.
+func ResourceAviUserAccountImporter(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	username := d.Id()
+	sess := aviSession(meta)
+	var robj interface{}
+	path := "api/useraccount"
+	if err := sess.Get(path, &robj); err != nil {
+		log.Printf("[ERROR] ResourceAviUserAccountImporter %v in GET of path %v\n", err, path)
+		return nil, err
+	}
+	// Passwords are never returned by the controller so only the account
+	// details are imported.
+	uobj, ok := robj.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response for %v: %v", path, robj)
+	}
+	if uobj["username"] != username {
+		return nil, fmt.Errorf("useraccount %v is not the account the provider is logged in as (%v)",
+			username, uobj["username"])
+	}
+	for _, k := range []string{"username", "name", "full_name", "email", "local"} {
+		if v, ok := uobj[k]; ok {
+			d.Set(k, v)
+		}
 	}
+	return []*schema.ResourceData{d}, nil
 }
 
 func ResourceAviUserAccountRead(d *schema.ResourceData, meta interface{}) error {
@@ -69,12 +104,12 @@ func resourceAviUserAccountCreate(d *schema.ResourceData, meta interface{}) erro
 func resourceAviUserAccountUpdate(d *schema.ResourceData, meta interface{}) error {
 	s := ResourceUserAccountSchema()
 	var err error
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	var robj interface{}
 	obj := d
 	if data, err := SchemaToAviData(obj, s); err == nil {
 		path := "api/useraccount"
-		err = client.AviSession.Put(path, data, &robj)
+		err = sess.Put(path, data, &robj)
 	}
 	return err
 }
@@ -0,0 +1,39 @@
+package avi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestResourceAviUserAccountImporter(t *testing.T) {
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/useraccount" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"username": "admin", "name": "admin", "full_name": "System Administrator",
+			"email": "admin@example.com", "local": true,
+		})
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviUserAccount().TestResourceData()
+	d.SetId("admin")
+	if _, err := ResourceAviUserAccountImporter(d, client); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if d.Get("username") != "admin" || d.Get("full_name") != "System Administrator" || d.Get("local") != true {
+		t.Errorf("imported username %v full_name %v local %v", d.Get("username"), d.Get("full_name"), d.Get("local"))
+	}
+
+	d = resourceAviUserAccount().TestResourceData()
+	d.SetId("operator")
+	_, err := ResourceAviUserAccountImporter(d, client)
+	if err == nil || !strings.Contains(err.Error(), "useraccount operator is not the account the provider is logged in as (admin)") {
+		t.Errorf("err = %v, expected a username mismatch", err)
+	}
+}
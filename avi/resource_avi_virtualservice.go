@@ -6,10 +6,10 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strings"
+	"time"
 )
 
 func ResourceVirtualServiceSchema() map[string]*schema.Schema {
@@ -401,13 +401,65 @@ func resourceAviVirtualService() *schema.Resource {
 		Read:   ResourceAviVirtualServiceRead,
 		Update: resourceAviVirtualServiceUpdate,
 		Delete: resourceAviVirtualServiceDelete,
-		Schema: ResourceVirtualServiceSchema(),
+		Schema: withVirtualServiceWait(withAllocatedVip(ResourceVirtualServiceSchema())),
 		Importer: &schema.ResourceImporter{
 			State: ResourceVirtualServiceImporter,
 		},
 	}
 }
 
+// withVirtualServiceWait adds the attributes that make create and update wait
+// for the virtual service to reach an operational state. They are not part of
+// the virtual service object.
+func withVirtualServiceWait(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["wait_for_oper_up"] = &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+	}
+	s["wait_timeout"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Optional: true,
+		Default:  600,
+	}
+	s["wait_oper_states"] = &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	return s
+}
+
+// waitForVirtualServiceOper waits up to wait_timeout seconds for the virtual
+// service of d to reach OPER_UP or one of wait_oper_states, when
+// wait_for_oper_up is set.
+func waitForVirtualServiceOper(d *schema.ResourceData, meta interface{}) error {
+	if !d.Get("wait_for_oper_up").(bool) {
+		return nil
+	}
+	states := map[string]bool{}
+	for _, state := range d.Get("wait_oper_states").([]interface{}) {
+		states[state.(string)] = true
+	}
+	if len(states) == 0 {
+		states["OPER_UP"] = true
+	}
+	uuid := UUIDFromID(d.Id())
+	timeout := time.Duration(d.Get("wait_timeout").(int)) * time.Second
+	return waitFor("virtual service "+uuid+" to be up", timeout, func() (bool, string, error) {
+		var runtime aviVsRuntimeSummary
+		path := "api/virtualservice/" + uuid + "/runtime"
+		if err := aviSession(meta).Get(path, &runtime); err != nil {
+			return false, err.Error(), nil
+		}
+		status := "state " + runtime.OperStatus.State
+		if len(runtime.OperStatus.Reason) > 0 {
+			status += ", reason " + strings.Join(runtime.OperStatus.Reason, "; ")
+		}
+		return states[runtime.OperStatus.State], status, nil
+	})
+}
+
 func ResourceVirtualServiceImporter(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
 	s := ResourceVirtualServiceSchema()
 	return ResourceImporter(d, m, "virtualservice", s)
@@ -418,6 +470,8 @@ func ResourceAviVirtualServiceRead(d *schema.ResourceData, meta interface{}) err
 	err := ApiRead(d, meta, "virtualservice", s)
 	if err != nil {
 		log.Printf("[ERROR] in reading object %v\n", err)
+	} else if d.Id() != "" {
+		err = setAllocatedVip(d, meta)
 	}
 	return err
 }
@@ -428,6 +482,12 @@ func resourceAviVirtualServiceCreate(d *schema.ResourceData, meta interface{}) e
 	if err == nil {
 		err = ResourceAviVirtualServiceRead(d, meta)
 	}
+	if err == nil {
+		// the id of the created virtual service is kept when the wait fails,
+		// which leaves it tainted in the state to be replaced by the next
+		// apply.
+		err = waitForVirtualServiceOper(d, meta)
+	}
 	return err
 }
 
@@ -436,10 +496,14 @@ func resourceAviVirtualServiceUpdate(d *schema.ResourceData, meta interface{}) e
 	var err error
 	var existingvirtualservice interface{}
 	var apiResponse interface{}
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	uuid := d.Get("uuid").(string)
+	// the update is computed from the object read here, keep other updates
+	// of it out until it is written.
+	objectLocks.Lock(uuid)
+	defer objectLocks.Unlock(uuid)
 	virtualservicepath := "api/virtualservice/" + uuid
-	err = client.AviSession.Get(virtualservicepath, &existingvirtualservice)
+	err = sess.Get(virtualservicepath, &existingvirtualservice)
 	if err == nil {
 		//adding default values to api_response before it overwrites the d (local state).
 		//Before GO lang sets zero value to fields which are absent in api response
@@ -473,6 +537,9 @@ func resourceAviVirtualServiceUpdate(d *schema.ResourceData, meta interface{}) e
 	if err == nil {
 		err = ResourceAviVirtualServiceRead(d, meta)
 	}
+	if err == nil {
+		err = waitForVirtualServiceOper(d, meta)
+	}
 	return err
 }
 
@@ -481,11 +548,9 @@ func resourceAviVirtualServiceDelete(d *schema.ResourceData, meta interface{}) e
 	if ApiDeleteSystemDefaultCheck(d) {
 		return nil
 	}
-	client := meta.(*clients.AviClient)
 	uuid := d.Get("uuid").(string)
 	if uuid != "" {
-		path := "api/" + objType + "/" + uuid
-		err := client.AviSession.Delete(path)
+		err := ApiDelete(meta, objType, uuid)
 		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
 			log.Println("[INFO] resourceAviVirtualServiceDelete not found")
 			return err
@@ -6,7 +6,6 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
 	"github.com/hashicorp/terraform/helper/schema"
 	"log"
 	"strings"
@@ -70,7 +69,7 @@ func resourceAviVsVip() *schema.Resource {
 		Read:   ResourceAviVsVipRead,
 		Update: resourceAviVsVipUpdate,
 		Delete: resourceAviVsVipDelete,
-		Schema: ResourceVsVipSchema(),
+		Schema: withAllocatedVip(ResourceVsVipSchema()),
 		Importer: &schema.ResourceImporter{
 			State: ResourceVsVipImporter,
 		},
@@ -87,6 +86,8 @@ func ResourceAviVsVipRead(d *schema.ResourceData, meta interface{}) error {
 	err := ApiRead(d, meta, "vsvip", s)
 	if err != nil {
 		log.Printf("[ERROR] in reading object %v\n", err)
+	} else if d.Id() != "" {
+		err = setAllocatedVip(d, meta)
 	}
 	return err
 }
@@ -105,10 +106,14 @@ func resourceAviVsVipUpdate(d *schema.ResourceData, meta interface{}) error {
 	var err error
 	var existingvsvip interface{}
 	var apiResponse interface{}
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	uuid := d.Get("uuid").(string)
+	// the update is computed from the object read here, keep other updates
+	// of it out until it is written.
+	objectLocks.Lock(uuid)
+	defer objectLocks.Unlock(uuid)
 	vsvippath := "api/vsvip/" + uuid
-	err = client.AviSession.Get(vsvippath, &existingvsvip)
+	err = sess.Get(vsvippath, &existingvsvip)
 	var vipobjs []interface{}
 	autoAllocFlag := false
 	if err == nil {
@@ -156,11 +161,9 @@ func resourceAviVsVipDelete(d *schema.ResourceData, meta interface{}) error {
 	if ApiDeleteSystemDefaultCheck(d) {
 		return nil
 	}
-	client := meta.(*clients.AviClient)
 	uuid := d.Get("uuid").(string)
 	if uuid != "" {
-		path := "api/" + objType + "/" + uuid
-		err := client.AviSession.Delete(path)
+		err := ApiDelete(meta, objType, uuid)
 		if err != nil && !(strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "204") || strings.Contains(err.Error(), "403")) {
 			log.Println("[INFO] resourceAviVsVipDelete not found")
 			return err
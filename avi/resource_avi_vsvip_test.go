@@ -21,14 +21,18 @@ func TestAVIVSVipBasic(t *testing.T) {
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAVIVSVipExists("avi_vsvip.testvsvip"),
 					resource.TestCheckResourceAttr(
-						"avi_vsvip.testvsvip", "name", "vsvip-test")),
+						"avi_vsvip.testvsvip", "name", "vsvip-test"),
+					resource.TestCheckResourceAttr(
+						"avi_vsvip.testvsvip", "allocated_ipv4", "1.2.3.1")),
 			},
 			{
 				Config: testAccUpdatedAVIVSVipConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckAVIVSVipExists("avi_vsvip.testvsvip"),
 					resource.TestCheckResourceAttr(
-						"avi_vsvip.testvsvip", "name", "vsvip-abc")),
+						"avi_vsvip.testvsvip", "name", "vsvip-abc"),
+					resource.TestCheckResourceAttr(
+						"avi_vsvip.testvsvip", "allocated_ipv4", "1.2.3.1")),
 			},
 		},
 	})
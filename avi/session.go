@@ -0,0 +1,229 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"crypto/tls"
+	"github.com/avinetworks/sdk/go/session"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// defaultMaxConcurrentRequests is the number of requests the provider sends
+// to the controller at the same time when max_concurrent_requests is not set.
+// It matches the default parallelism of terraform.
+const defaultMaxConcurrentRequests = 10
+
+// aviSessionPool hands out controller sessions to concurrent resource
+// operations. The SDK session keeps its csrf token and session id in
+// unsynchronized fields that are updated from every response and it logs in
+// again on a 401, so a session must never serve two requests at once. The
+// pool holds up to size logged in sessions, gives each request exclusive use
+// of one of them and so also bounds the number of requests in flight. Logins
+// of new sessions are serialized, and so are the logins the SDK runs again
+// when a request of a session gets a 401, see newLoginTransport.
+type aviSessionPool struct {
+	// idle holds logged in sessions that no request is using.
+	idle chan *session.AviSession
+	// slots holds one token per request in flight.
+	slots chan struct{}
+	// login is held by every login request of the sessions of the pool.
+	login *sync.Mutex
+	// create serializes the creation of new sessions.
+	create *sync.Mutex
+	// newSession logs in a new session. When nil the pool only uses the
+	// session it was created with.
+	newSession func() (*session.AviSession, error)
+	// defaultTenant is the tenant the sessions were logged in with.
+	defaultTenant string
+	// tenant, when set, is the tenant requests are sent in instead of
+	// defaultTenant. See inTenant.
+	tenant string
+}
+
+// newAviSessionPool returns a pool of up to size sessions. login is the mutex
+// of the transport of the sessions, see newLoginTransport, or nil when the
+// sessions log in without it.
+func newAviSessionPool(primary *session.AviSession, size int, defaultTenant string,
+	newSession func() (*session.AviSession, error), login *sync.Mutex) *aviSessionPool {
+	if size < 1 {
+		size = 1
+	}
+	if login == nil {
+		login = &sync.Mutex{}
+	}
+	pool := &aviSessionPool{
+		idle:          make(chan *session.AviSession, size),
+		slots:         make(chan struct{}, size),
+		login:         login,
+		create:        &sync.Mutex{},
+		newSession:    newSession,
+		defaultTenant: defaultTenant,
+	}
+	pool.idle <- primary
+	return pool
+}
+
+// aviSession returns the session pool of the provider that created meta.
+// Every API call of the provider goes through it.
+func aviSession(meta interface{}) *aviSessionPool {
+	return getProviderSettings(meta).sessions
+}
+
+// acquire waits for a free request slot and returns a session for the
+// exclusive use of the caller. A new session is logged in only when all
+// existing sessions are busy, so the pool never holds more sessions than
+// slots.
+func (pool *aviSessionPool) acquire() (*session.AviSession, error) {
+	pool.slots <- struct{}{}
+	select {
+	case sess := <-pool.idle:
+		return sess, nil
+	default:
+	}
+	if pool.newSession == nil {
+		return <-pool.idle, nil
+	}
+	pool.create.Lock()
+	defer pool.create.Unlock()
+	// a session may have been released while waiting for the login.
+	select {
+	case sess := <-pool.idle:
+		return sess, nil
+	default:
+	}
+	log.Printf("[DEBUG] aviSessionPool logging in a new session\n")
+	sess, err := pool.newSession()
+	if err != nil {
+		log.Printf("[ERROR] aviSessionPool login failed %v\n", err)
+		<-pool.slots
+		return nil, err
+	}
+	return sess, nil
+}
+
+// newLoginTransport returns the transport of the sessions of a pool. The SDK
+// session logs in again on its own when a request gets a 401, which happens
+// to every session of the pool at once when their sessions expire together.
+// The transport holds login while a login request is in flight, so that a
+// single login runs at a time; the request is retried by the SDK once its
+// session is logged in.
+func newLoginTransport(login *sync.Mutex) *http.Transport {
+	transport := &http.Transport{}
+	rt := &loginRoundTripper{
+		base:  &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		login: login,
+	}
+	transport.RegisterProtocol("https", rt)
+	return transport
+}
+
+// loginRoundTripper sends requests with base and holds login around login
+// requests.
+type loginRoundTripper struct {
+	base  http.RoundTripper
+	login *sync.Mutex
+}
+
+func (rt *loginRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == "POST" && strings.HasSuffix(req.URL.Path, "/login") {
+		rt.login.Lock()
+		defer rt.login.Unlock()
+	}
+	return rt.base.RoundTrip(req)
+}
+
+func (pool *aviSessionPool) release(sess *session.AviSession) {
+	pool.idle <- sess
+	<-pool.slots
+}
+
+// inTenant returns a view of the pool that sends its requests in tenant. It
+// shares the sessions and request slots of the pool.
+func (pool *aviSessionPool) inTenant(tenant string) *aviSessionPool {
+	view := *pool
+	view.tenant = tenant
+	return &view
+}
+
+// do runs fn with a session acquired from the pool.
+func (pool *aviSessionPool) do(fn func(sess *session.AviSession) error) error {
+	sess, err := pool.acquire()
+	if err != nil {
+		return err
+	}
+	defer pool.release(sess)
+	if pool.tenant != "" && pool.tenant != pool.defaultTenant {
+		// the session is switched back before another request can use it.
+		session.SetTenant(pool.tenant)(sess)
+		defer session.SetTenant(pool.defaultTenant)(sess)
+	}
+	return fn(sess)
+}
+
+// Get issues a GET request on a pooled session.
+func (pool *aviSessionPool) Get(uri string, response interface{}) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.Get(uri, response)
+	})
+}
+
+// GetRaw issues a GET request on a pooled session and returns the raw
+// response body.
+func (pool *aviSessionPool) GetRaw(uri string) ([]byte, error) {
+	var resp []byte
+	err := pool.do(func(sess *session.AviSession) error {
+		var err error
+		resp, err = sess.GetRaw(uri)
+		return err
+	})
+	return resp, err
+}
+
+// Post issues a POST request on a pooled session.
+func (pool *aviSessionPool) Post(uri string, payload interface{}, response interface{}) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.Post(uri, payload, response)
+	})
+}
+
+// Put issues a PUT request on a pooled session.
+func (pool *aviSessionPool) Put(uri string, payload interface{}, response interface{}) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.Put(uri, payload, response)
+	})
+}
+
+// Patch issues a PATCH request on a pooled session.
+func (pool *aviSessionPool) Patch(uri string, payload interface{}, patchOp string, response interface{}) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.Patch(uri, payload, patchOp, response)
+	})
+}
+
+// Delete issues a DELETE request on a pooled session.
+func (pool *aviSessionPool) Delete(uri string, params ...interface{}) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.Delete(uri, params...)
+	})
+}
+
+// PostMultipartRequest uploads a file on a pooled session.
+func (pool *aviSessionPool) PostMultipartRequest(verb string, uri string, file *os.File) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.PostMultipartRequest(verb, uri, file)
+	})
+}
+
+// GetMultipartRaw downloads a file on a pooled session.
+func (pool *aviSessionPool) GetMultipartRaw(verb string, uri string, file *os.File) error {
+	return pool.do(func(sess *session.AviSession) error {
+		return sess.GetMultipartRaw(verb, uri, file)
+	})
+}
@@ -0,0 +1,179 @@
+package avi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avinetworks/sdk/go/session"
+)
+
+func TestAviSessionPoolConcurrency(t *testing.T) {
+	var lock sync.Mutex
+	logins, inFlight, maxInFlight, loginsInFlight := 0, 0, 0, 0
+	sessionInFlight := map[string]int{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.WriteHeader(http.StatusOK)
+			return
+		case "/login":
+			lock.Lock()
+			logins++
+			loginsInFlight++
+			if loginsInFlight > 1 {
+				t.Errorf("%d logins in flight", loginsInFlight)
+			}
+			id := fmt.Sprintf("session-%d", logins)
+			lock.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			lock.Lock()
+			loginsInFlight--
+			lock.Unlock()
+			http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: id})
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: id})
+			fmt.Fprint(w, "{}")
+			return
+		}
+		cookie, err := r.Cookie("sessionid")
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		lock.Lock()
+		inFlight++
+		sessionInFlight[cookie.Value]++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		if sessionInFlight[cookie.Value] > 1 {
+			t.Errorf("session %v used by %d requests at once", cookie.Value, sessionInFlight[cookie.Value])
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		lock.Lock()
+		inFlight--
+		sessionInFlight[cookie.Value]--
+		lock.Unlock()
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	newSession := func() (*session.AviSession, error) {
+		return session.NewAviSession(host, "admin", session.SetPassword("admin"), session.SetInsecure)
+	}
+	primary, err := newSession()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	pool := newAviSessionPool(primary, 3, "admin", newSession, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var res interface{}
+			if err := pool.Get("api/pool", &res); err != nil {
+				t.Errorf("err: %s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 3 {
+		t.Fatalf("expected at most 3 requests in flight, got %d", maxInFlight)
+	}
+	if logins > 3 {
+		t.Fatalf("expected at most 3 logins, got %d", logins)
+	}
+}
+
+func TestAviSessionPoolExpiredSessions(t *testing.T) {
+	var lock sync.Mutex
+	logins, loginsInFlight, maxLoginsInFlight := 0, 0, 0
+	valid := map[string]bool{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/", "//api/cluster/status":
+			w.WriteHeader(http.StatusOK)
+			return
+		case "/login":
+			lock.Lock()
+			logins++
+			loginsInFlight++
+			if loginsInFlight > maxLoginsInFlight {
+				maxLoginsInFlight = loginsInFlight
+			}
+			id := fmt.Sprintf("session-%d", logins)
+			valid[id] = true
+			lock.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			lock.Lock()
+			loginsInFlight--
+			lock.Unlock()
+			http.SetCookie(w, &http.Cookie{Name: "csrftoken", Value: id})
+			http.SetCookie(w, &http.Cookie{Name: "sessionid", Value: id})
+			fmt.Fprint(w, "{}")
+			return
+		}
+		cookie, err := r.Cookie("sessionid")
+		lock.Lock()
+		ok := err == nil && valid[cookie.Value]
+		lock.Unlock()
+		if !ok {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+		fmt.Fprint(w, "{}")
+	}))
+	defer server.Close()
+
+	host := strings.TrimPrefix(server.URL, "https://")
+	login := &sync.Mutex{}
+	newSession := func() (*session.AviSession, error) {
+		return session.NewAviSession(host, "admin", session.SetPassword("admin"), session.SetInsecure,
+			session.SetTransport(newLoginTransport(login)))
+	}
+	primary, err := newSession()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	pool := newAviSessionPool(primary, 4, "admin", newSession, login)
+	get := func(n int) {
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				var res interface{}
+				if err := pool.Get("api/pool", &res); err != nil {
+					t.Errorf("err: %s", err)
+				}
+			}()
+		}
+		wg.Wait()
+	}
+	get(8)
+
+	// the sessions of the pool expire together and log in again one at a
+	// time.
+	lock.Lock()
+	sessions := len(valid)
+	valid = map[string]bool{}
+	maxLoginsInFlight = 0
+	lock.Unlock()
+	get(8)
+	if maxLoginsInFlight != 1 {
+		t.Errorf("expected a single login in flight, got %d", maxLoginsInFlight)
+	}
+	if logins <= sessions {
+		t.Errorf("expected the expired sessions to log in again, got %d logins for %d sessions", logins, sessions)
+	}
+}
@@ -6,12 +6,13 @@
 package avi
 
 import (
-	"github.com/avinetworks/sdk/go/clients"
-	"github.com/avinetworks/sdk/go/session"
+	"encoding/json"
+	"fmt"
 	"github.com/hashicorp/terraform/helper/hashcode"
 	"github.com/hashicorp/terraform/helper/schema"
 	"io/ioutil"
 	"log"
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -65,12 +66,41 @@ func SchemaToAviData(d interface{}, s map[string]*schema.Schema) (interface{}, e
 				log.Printf("[ERROR] SchemaToAviData %v in converting k: %v v: %v", err, k, v)
 			}
 		}
+		if err := mergeExtraConfigJSON(m, r); err != nil {
+			log.Printf("[ERROR] SchemaToAviData %v", err)
+			return nil, err
+		}
 		return m, nil
 	}
 	/** Return the same object as there is nothing special about **/
 	return d, nil
 }
 
+// schemaToModel converts a schema attribute value into an SDK model by
+// round tripping it through its Avi JSON representation. Objects returned by
+// the API are converted with jsonToModel, as SchemaToAviData drops their empty
+// values.
+func schemaToModel(v interface{}, model interface{}) error {
+	data, err := SchemaToAviData(v, nil)
+	if err != nil {
+		return err
+	}
+	jdata, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(jdata, model)
+}
+
+// jsonToModel converts an object returned by the API into an SDK model.
+func jsonToModel(obj interface{}, model interface{}) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, model)
+}
+
 func CommonHash(v interface{}) int {
 	return hashcode.String("avi")
 }
@@ -80,6 +110,10 @@ func SetDefaultsInAPIRes(api_res interface{}, d_local interface{}, s map[string]
 	default:
 	case map[string]interface{}:
 		for k, v := range d_local.(map[string]interface{}) {
+			if _, ok := s[k]; !ok {
+				// fields merged from extra_config_json have no schema and no defaults.
+				continue
+			}
 			switch v.(type) {
 			//Getting key, value for given d_local
 			default:
@@ -242,110 +276,165 @@ func SetIDFromObj(d *schema.ResourceData, robj interface{}) {
 
 func ApiCreateOrUpdate(d *schema.ResourceData, meta interface{}, objType string, s map[string]*schema.Schema,
 	opts ...bool) error {
-	client := meta.(*clients.AviClient)
-	var robj interface{}
-	obj := d
-
 	usePatchForUpdate := false
 	if len(opts) > 0 {
 		usePatchForUpdate = opts[0]
 	}
+	data, err := SchemaToAviData(d, s)
+	if err != nil {
+		log.Printf("[ERROR] ApiCreateOrUpdate: Error %v", err)
+		return err
+	}
+	return apiCreateOrUpdateData(d, meta, objType, data, usePatchForUpdate)
+}
 
-	if data, err := SchemaToAviData(obj, s); err == nil {
-		path := "api/" + objType
-		specialobj := IsPostNotAllowed(objType)
-		if specialobj {
-			path = path + "?skip_default=true"
-			err = client.AviSession.Put(path, data, &robj)
-			if err != nil {
-				log.Printf("[ERROR] ApiCreateOrUpdate: PUT on %v Error %v path %v id %v\n", objType, err, path,
-					d.Id())
-			}
-		} else if uuid, ok := d.GetOk("uuid"); ok {
-			path = path + "/" + uuid.(string) + "?skip_default=true"
-			if !usePatchForUpdate {
-				err = client.AviSession.Put(path, data, &robj)
-			} else {
-				err = client.AviSession.Patch(path, data, "replace", &robj)
-			}
-			if err != nil {
-				log.Printf("[ERROR] ApiCreateOrUpdate: PUT Error %v path %v id %v\n", err, path, d.Id())
-			}
+// apiCreateOrUpdateData writes data, the API representation of the object of
+// d, to the controller. The object is updated when d has a uuid or an object
+// of objType with the name of data exists, and created otherwise. The id of d
+// is set to the written object.
+func apiCreateOrUpdateData(d *schema.ResourceData, meta interface{}, objType string, data interface{},
+	usePatchForUpdate bool) error {
+	sess := aviSession(meta)
+	var robj interface{}
+	var err error
+	defer func() {
+		uuid := ""
+		if m, ok := robj.(map[string]interface{}); ok {
+			uuid, _ = m["uuid"].(string)
+		}
+		if uuid == "" && d.Id() != "" {
+			uuid = UUIDFromID(d.Id())
+		}
+		invalidateObjectCache(meta, objType, uuid)
+	}()
+
+	dataMap, _ := data.(map[string]interface{})
+	path := "api/" + objType
+	specialobj := IsPostNotAllowed(objType)
+	if specialobj {
+		path = path + "?skip_default=true"
+		err = sess.Put(path, data, &robj)
+		if err != nil {
+			log.Printf("[ERROR] ApiCreateOrUpdate: PUT on %v Error %v path %v id %v\n", objType, err, path,
+				d.Id())
+		}
+	} else if uuid, ok := d.GetOk("uuid"); ok {
+		path = path + "/" + uuid.(string) + "?skip_default=true"
+		if !usePatchForUpdate {
+			err = sess.Put(path, data, &robj)
 		} else {
-			if name, ok := d.GetOk("name"); ok {
-				var existing_obj interface{}
-				if cloudRef, ok := d.GetOk("cloud_ref"); ok && strings.Contains(cloudRef.(string),
-					"api/cloud/") {
-					cloudUUID := strings.SplitN(cloudRef.(string), "api/cloud/", 2)[1]
-					// strip the # if it exists
-					cloudUUID = strings.Split(cloudUUID, "#")[0]
-					log.Printf("[INFO] ApiCreateOrUpdate: using cloud %v for obj %v name %s \n",
-						cloudUUID, objType, name)
-					err = client.AviSession.GetObject(objType, session.SetName(name.(string)),
-						session.SetResult(&existing_obj), session.SetCloudUUID(cloudUUID),
-						session.SetSkipDefault(true))
-					if err != nil {
-						log.Printf("[ERROR] ApiCreateOrUpdate: GET Error %v path %v id %v\n", err, path, d.Id())
-					}
-				} else {
-					log.Printf("[INFO] ApiCreateOrUpdate: reading obj %v name %s \n",
-						objType, name)
-					err = client.AviSession.GetObject(objType, session.SetName(name.(string)),
-						session.SetResult(&existing_obj), session.SetSkipDefault(true))
-					if err != nil {
-						log.Printf("[ERROR] ApiCreateOrUpdate: GET Error %v path %v id %v\n", err, path, d.Id())
-					}
+			err = sess.Patch(path, data, "replace", &robj)
+		}
+		if err != nil {
+			log.Printf("[ERROR] ApiCreateOrUpdate: PUT Error %v path %v id %v\n", err, path, d.Id())
+		}
+	} else {
+		if name, ok := dataMap["name"].(string); ok && name != "" {
+			var existing_obj interface{}
+			if cloudRef, ok := dataMap["cloud_ref"].(string); ok && strings.Contains(cloudRef,
+				"api/cloud/") {
+				cloudUUID := strings.SplitN(cloudRef, "api/cloud/", 2)[1]
+				// strip the # if it exists
+				cloudUUID = strings.Split(cloudUUID, "#")[0]
+				log.Printf("[INFO] ApiCreateOrUpdate: using cloud %v for obj %v name %s \n",
+					cloudUUID, objType, name)
+				existing_obj, err = ApiGetObjectByName(meta, objType, name, cloudUUID)
+				if err != nil {
+					log.Printf("[ERROR] ApiCreateOrUpdate: GET Error %v path %v id %v\n", err, path, d.Id())
+				}
+			} else {
+				log.Printf("[INFO] ApiCreateOrUpdate: reading obj %v name %s \n",
+					objType, name)
+				existing_obj, err = ApiGetObjectByName(meta, objType, name, "")
+				if err != nil {
+					log.Printf("[ERROR] ApiCreateOrUpdate: GET Error %v path %v id %v\n", err, path, d.Id())
 				}
+			}
 
-				if existing_obj == nil {
-					// object not found
-					log.Printf("[INFO] ApiCreateOrUpdate: Creating obj type %v schema %v data %v\n", objType, d,
-						data)
-					err = client.AviSession.Post(path, data, &robj)
-					if err == nil && robj != nil {
-						SetIDFromObj(d, robj)
-					} else {
-						log.Printf("[ERROR] ApiCreateOrUpdate creation failed %v object with name %v\n", err,
-							name)
-					}
+			if existing_obj == nil {
+				// object not found
+				log.Printf("[INFO] ApiCreateOrUpdate: Creating obj type %v schema %v data %v\n", objType, d,
+					data)
+				err = sess.Post(path, data, &robj)
+				if err == nil && robj != nil {
+					SetIDFromObj(d, robj)
 				} else {
-					// found existing object.
-					SetIDFromObj(d, existing_obj)
-					uuid = existing_obj.(map[string]interface{})["uuid"].(string)
-					path = path + "/" + uuid.(string) + "?skip_default=true"
-					if !usePatchForUpdate {
-						err = client.AviSession.Put(path, data, &robj)
-					} else {
-						err = client.AviSession.Patch(path, data, "replace", &robj)
-					}
-					if err != nil {
-						log.Printf("[ERROR] ApiCreateOrUpdate: PUT Error %v path %v id %v\n", err, path, d.Id())
-					}
+					log.Printf("[ERROR] ApiCreateOrUpdate creation failed %v object with name %v\n", err,
+						name)
 				}
 			} else {
-				log.Printf("[INFO] ApiCreateOrUpdate: Creating obj %v schema %v data %v\n", objType, d, data)
-				err = client.AviSession.Post(path, data, &robj)
-				if err != nil {
-					log.Printf("[ERROR] ApiCreateOrUpdate creation failed %v\n", err)
+				// found existing object.
+				SetIDFromObj(d, existing_obj)
+				uuid = existing_obj.(map[string]interface{})["uuid"].(string)
+				path = path + "/" + uuid.(string) + "?skip_default=true"
+				if !usePatchForUpdate {
+					err = sess.Put(path, data, &robj)
 				} else {
-					SetIDFromObj(d, robj)
+					err = sess.Patch(path, data, "replace", &robj)
+				}
+				if err != nil {
+					log.Printf("[ERROR] ApiCreateOrUpdate: PUT Error %v path %v id %v\n", err, path, d.Id())
 				}
 			}
+		} else {
+			log.Printf("[INFO] ApiCreateOrUpdate: Creating obj %v schema %v data %v\n", objType, d, data)
+			err = sess.Post(path, data, &robj)
+			if err != nil {
+				log.Printf("[ERROR] ApiCreateOrUpdate creation failed %v\n", err)
+			} else {
+				SetIDFromObj(d, robj)
+			}
 		}
-		return err
-	} else {
-		log.Printf("[ERROR] ApiCreateOrUpdate: Error %v", err)
-		return err
 	}
-	return nil
+	return err
 }
 
 func ApiRead(d *schema.ResourceData, meta interface{}, objType string, s map[string]*schema.Schema) error {
-	client := meta.(*clients.AviClient)
+	uuid := ""
+	url := ""
+	obj := apiReadObject(d, meta, objType)
+	if obj == nil {
+		return nil
+	}
+	if local_data, err := SchemaToAviData(d, s); err == nil {
+		mod_api_res, err := SetDefaultsInAPIRes(obj, local_data, s)
+		if err != nil {
+			log.Printf("[ERROR] ApiRead in modifying api response object %v\n", err)
+		}
+		if _, err := ApiDataToSchema(mod_api_res, d, s); err == nil {
+			setExtraConfigJSON(d, obj)
+			if mod_api_res.(map[string]interface{})["uuid"] != nil {
+				uuid = mod_api_res.(map[string]interface{})["uuid"].(string)
+			}
+			if mod_api_res.(map[string]interface{})["url"] != nil {
+				url = mod_api_res.(map[string]interface{})["url"].(string)
+			}
+			//url = strings.SplitN(url, "#", 2)[0]
+			if url != "" {
+				d.SetId(url)
+				log.Printf("[DEBUG] ApiRead read object with id %v\n", url)
+			} else {
+				d.SetId(uuid)
+				log.Printf("[DEBUG] ApiRead read object with id %v\n", uuid)
+			}
+		} else {
+			log.Printf("[ERROR] ApiRead in setting read object %v\n", err)
+		}
+		log.Printf("[DEBUG] type: %v local_data : %v", objType, local_data)
+		log.Printf("[DEBUG] type: %v mod_api_res: %v", objType, mod_api_res)
+	}
+
+	return nil
+}
+
+// apiReadObject reads the object of objType that d refers to by its id, uuid
+// or name. It returns nil and clears the id of d when the object does not
+// exist.
+func apiReadObject(d *schema.ResourceData, meta interface{}, objType string) interface{} {
+	sess := aviSession(meta)
 	var obj interface{}
 	var path string
 	uuid := ""
-	url := ""
 	specialobj := IsPostNotAllowed(objType)
 	log.Printf("[DEBUG] ApiRead reading object with objType %v id %v\n", objType, d.Id())
 	if d.Id() != "" {
@@ -365,12 +454,19 @@ func ApiRead(d *schema.ResourceData, meta interface{}, objType string, s map[str
 		} else {
 			path = "api/" + objType + "/" + uuid + "?skip_default=true"
 		}
-		log.Printf("[DEBUG] ApiRead reading object with id %v path %v\n", uuid, path)
-		err := client.AviSession.Get(path, &obj)
-		if err != nil {
-			d.SetId("")
-			log.Printf("[ERROR] ApiRead object with uuid %v not found err %v\n", uuid, err)
-			return nil
+		if cache := getObjectCache(meta); cache != nil && !specialobj {
+			if cobj, ok := cache.Get(meta, objType, uuid); ok && cobj != nil {
+				obj = cobj
+			}
+		}
+		if obj == nil {
+			log.Printf("[DEBUG] ApiRead reading object with id %v path %v\n", uuid, path)
+			err := sess.Get(path, &obj)
+			if err != nil {
+				d.SetId("")
+				log.Printf("[ERROR] ApiRead object with uuid %v not found err %v\n", uuid, err)
+				return nil
+			}
 		}
 	} else if name, ok := d.GetOk("name"); ok {
 		var err error
@@ -379,12 +475,10 @@ func ApiRead(d *schema.ResourceData, meta interface{}, objType string, s map[str
 			cloudUUID = strings.Split(cloudUUID, "#")[0]
 			log.Printf("[DEBUG] ApiRead using cloud %v obj %v name %v\n", cloudUUID,
 				objType, name)
-			err = client.AviSession.GetObject(objType, session.SetName(name.(string)),
-				session.SetResult(&obj), session.SetCloudUUID(cloudUUID), session.SetSkipDefault(true))
+			obj, err = apiReadObjectByName(meta, objType, name.(string), cloudUUID)
 		} else {
 			log.Printf("[DEBUG] ApiRead using name %v \n", name)
-			err = client.AviSession.GetObject(objType, session.SetName(name.(string)),
-				session.SetResult(&obj), session.SetSkipDefault(true))
+			obj, err = apiReadObjectByName(meta, objType, name.(string), "")
 		}
 		if err != nil {
 			d.SetId("")
@@ -394,7 +488,7 @@ func ApiRead(d *schema.ResourceData, meta interface{}, objType string, s map[str
 	} else if specialobj {
 		path := "api/" + objType
 		log.Printf("[DEBUG] ApiRead reading special object with path %v\n", path)
-		err := client.AviSession.Get(path, &obj)
+		err := sess.Get(path, &obj)
 		if err != nil {
 			d.SetId("")
 			log.Printf("[ERROR] ApiRead special object with path %v not found err %v\n", path, err)
@@ -405,34 +499,7 @@ func ApiRead(d *schema.ResourceData, meta interface{}, objType string, s map[str
 		log.Printf("[ERROR] ApiRead not found %v\n", d.Get("uuid"))
 		return nil
 	}
-	if local_data, err := SchemaToAviData(d, s); err == nil {
-		mod_api_res, err := SetDefaultsInAPIRes(obj, local_data, s)
-		if err != nil {
-			log.Printf("[ERROR] ApiRead in modifying api response object %v\n", err)
-		}
-		if _, err := ApiDataToSchema(mod_api_res, d, s); err == nil {
-			if mod_api_res.(map[string]interface{})["uuid"] != nil {
-				uuid = mod_api_res.(map[string]interface{})["uuid"].(string)
-			}
-			if mod_api_res.(map[string]interface{})["url"] != nil {
-				url = mod_api_res.(map[string]interface{})["url"].(string)
-			}
-			//url = strings.SplitN(url, "#", 2)[0]
-			if url != "" {
-				d.SetId(url)
-				log.Printf("[DEBUG] ApiRead read object with id %v\n", url)
-			} else {
-				d.SetId(uuid)
-				log.Printf("[DEBUG] ApiRead read object with id %v\n", uuid)
-			}
-		} else {
-			log.Printf("[ERROR] ApiRead in setting read object %v\n", err)
-		}
-		log.Printf("[DEBUG] type: %v local_data : %v", objType, local_data)
-		log.Printf("[DEBUG] type: %v mod_api_res: %v", objType, mod_api_res)
-	}
-
-	return nil
+	return obj
 }
 
 func ResourceImporter(d *schema.ResourceData, meta interface{}, objType string, s map[string]*schema.Schema) ([]*schema.ResourceData, error) {
@@ -440,37 +507,37 @@ func ResourceImporter(d *schema.ResourceData, meta interface{}, objType string,
 		// return the ID based import
 		return []*schema.ResourceData{d}, nil
 	}
-	var data interface{}
-	client := meta.(*clients.AviClient)
+	var results []*schema.ResourceData
 	path := "api/" + objType + "?skip_default=true"
-	err := client.AviSession.Get(path, &data)
+	err := ApiCollectionIterate(meta, path, func(obj map[string]interface{}) error {
+		log.Printf("[DEBUG] ResourceImporter processing obj %v\n", obj)
+		result := new(schema.ResourceData)
+		if _, err := ApiDataToSchema(obj, result, s); err == nil {
+			url := obj["url"].(string)
+			uuid := obj["uuid"].(string)
+			//url = strings.SplitN(url, "#", 2)[0]
+			result.SetId(url)
+			result.Set("uuid", uuid)
+			result.SetType("avi_" + objType)
+			results = append(results, result)
+		}
+		return nil
+	})
 	if err != nil {
 		log.Printf("[ERROR] ResourceImporter %v in GET of path %v\n", err, path)
 		return nil, err
 	}
-	count := int((data.(map[string]interface{})["count"]).(float64))
-	log.Printf("[DEBUG] ResourceImporter read data with path %v -> count %v\n", path, count)
-	if count > 0 {
-		results := make([]*schema.ResourceData, count)
-		apiResults := (data.(map[string]interface{})["results"]).([]interface{})
-		for index := 0; index < count; index++ {
-			obj := apiResults[index].(map[string]interface{})
-			log.Printf("[DEBUG] ResourceImporter processing obj %v results %v\n", obj, results[index])
-			result := new(schema.ResourceData)
-			if _, err := ApiDataToSchema(obj, result, s); err == nil {
-				log.Printf("[DEBUG] ResourceImporter Processing obj %v\n", obj)
-				url := obj["url"].(string)
-				uuid := obj["uuid"].(string)
-				//url = strings.SplitN(url, "#", 2)[0]
-				result.SetId(url)
-				result.Set("uuid", uuid)
-				result.SetType("avi_" + objType)
-				results[index] = result
-			}
-		}
-		return results, nil
-	}
-	return nil, nil
+	log.Printf("[DEBUG] ResourceImporter read data with path %v -> count %v\n", path, len(results))
+	return results, nil
+}
+
+// ApiDelete deletes the object of objType with the given uuid. The error is
+// returned as is so callers can decide which status codes to ignore.
+func ApiDelete(meta interface{}, objType string, uuid string) error {
+	sess := aviSession(meta)
+	path := "api/" + objType + "/" + uuid
+	defer invalidateObjectCache(meta, objType, uuid)
+	return sess.Delete(path)
 }
 
 func ApiDeleteSystemDefaultCheck(d *schema.ResourceData) bool {
@@ -520,7 +587,7 @@ func createFilePointer(path string) (*os.File, error) {
 
 //Function to make REST API call for upload and download.
 func MultipartUploadOrDownload(d *schema.ResourceData, meta interface{}, s map[string]*schema.Schema) error {
-	client := meta.(*clients.AviClient)
+	sess := aviSession(meta)
 	uri := d.Get("uri").(string)
 	local_file := d.Get("local_file").(string)
 	var err error
@@ -540,7 +607,7 @@ func MultipartUploadOrDownload(d *schema.ResourceData, meta interface{}, s map[s
 				"license_text": str_data,
 			}
 			uri = "/api/" + uri
-			err = client.AviSession.Put(uri, license_data, &res)
+			err = sess.Put(uri, license_data, &res)
 			if err != nil {
 				log.Printf("[ERROR] MultipartUploadOrDownload %v in PUT of URI %v\n", err, uri)
 				return err
@@ -564,7 +631,7 @@ func MultipartUploadOrDownload(d *schema.ResourceData, meta interface{}, s map[s
 				return err
 			}
 			local_file_ptr := mustOpen(local_file)
-			err := client.AviSession.PostMultipartRequest("POST", uri, local_file_ptr)
+			err := sess.PostMultipartRequest("POST", uri, local_file_ptr)
 			if err != nil {
 				log.Printf("[ERROR] MultipartUploadOrDownload Error uploading file %v %v", local_file, err)
 				return err
@@ -579,7 +646,7 @@ func MultipartUploadOrDownload(d *schema.ResourceData, meta interface{}, s map[s
 		if err != nil {
 			log.Printf("[ERROR] MultipartUploadOrDownload Error for creation of file %v", local_file)
 		}
-		err = client.AviSession.GetMultipartRaw("GET", uri, download_file_ptr)
+		err = sess.GetMultipartRaw("GET", uri, download_file_ptr)
 		if err != nil {
 			log.Printf("[ERROR] MultipartUploadOrDownload Error downloaing file using uri %v %v", uri, err)
 			return err
@@ -593,7 +660,7 @@ func MultipartUploadOrDownload(d *schema.ResourceData, meta interface{}, s map[s
 		if err != nil {
 			log.Printf("[ERROR] MultipartUploadOrDownload Error for creation of file %v", local_file)
 		}
-		err = client.AviSession.GetMultipartRaw("GET", uri, download_file_ptr)
+		err = sess.GetMultipartRaw("GET", uri, download_file_ptr)
 		if err != nil {
 			log.Printf("[ERROR] MultipartUploadOrDownload Error downloaing file using uri %v %v", uri, err)
 			return err
@@ -623,3 +690,86 @@ func IsPostNotAllowed(objtype string) bool {
 	}
 	return specialobj
 }
+
+// dataSourceObjectUUID returns the uuid argument of d or, when only name is
+// set, the uuid of the only object of objType with that name, as the singular
+// data sources do.
+func dataSourceObjectUUID(d *schema.ResourceData, meta interface{}, objType string) (string, error) {
+	if uuid, ok := d.GetOk("uuid"); ok {
+		return uuid.(string), nil
+	}
+	if name, ok := d.GetOk("name"); ok {
+		return dataSourceFindByName(d, meta, objType, name.(string))
+	}
+	return "", fmt.Errorf("either name or uuid of the %v must be set", objType)
+}
+
+// getRuntimeResults reads a runtime endpoint into results. Runtime endpoints
+// return either a list or a collection page with the list in results.
+func getRuntimeResults(meta interface{}, path string, results interface{}) error {
+	resp, err := aviSession(meta).GetRaw(path)
+	if err != nil {
+		log.Printf("[ERROR] getRuntimeResults %v in GET of %v\n", err, path)
+		return err
+	}
+	var page struct {
+		Results json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(resp, &page); err == nil && page.Results != nil {
+		resp = page.Results
+	}
+	return json.Unmarshal(resp, results)
+}
+
+// entityRefUUID returns the object type and uuid of ref, the value of the
+// argument k, which is either the url of an object of one of objTypes or a
+// reference by name such as /api/pool/?name=web.
+func entityRefUUID(meta interface{}, k string, ref string, objTypes []string) (string, string, error) {
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid %v %q: %v", k, ref, err)
+	}
+	var objType, uuid string
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, part := range parts {
+		if part == "api" && i+1 < len(parts) {
+			objType = parts[i+1]
+			if i+2 < len(parts) {
+				uuid = UUIDFromID(parts[i+2])
+			}
+		}
+	}
+	valid := false
+	for _, t := range objTypes {
+		valid = valid || t == objType
+	}
+	if !valid {
+		return "", "", fmt.Errorf("invalid %v %q: expected a reference to a %v",
+			k, ref, strings.Join(objTypes, ", "))
+	}
+	if uuid != "" {
+		return objType, uuid, nil
+	}
+	name := u.Query().Get("name")
+	if name == "" {
+		return "", "", fmt.Errorf("invalid %v %q: missing uuid or name", k, ref)
+	}
+	obj, err := apiReadObjectByName(meta, objType, name, "")
+	if err != nil {
+		log.Printf("[ERROR] entityRefUUID %v in reading %v %v\n", err, objType, name)
+		return "", "", err
+	}
+	uuid, _ = obj.(map[string]interface{})["uuid"].(string)
+	return objType, uuid, nil
+}
+
+// refFilterUUID returns the uuid of the object of objType that ref, the value
+// of the filter argument k, refers to. A plain uuid is accepted as well, as in
+// the plural data sources.
+func refFilterUUID(meta interface{}, k string, ref string, objType string) (string, error) {
+	if !strings.Contains(ref, "/") {
+		return ref, nil
+	}
+	_, uuid, err := entityRefUUID(meta, k, ref, []string{objType})
+	return uuid, err
+}
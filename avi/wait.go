@@ -0,0 +1,37 @@
+/*
+ * Copyright (c) 2017. Avi Networks.
+ * Author: Gaurav Rastogi (grastogi@avinetworks.com)
+ *
+ */
+package avi
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// waitPollInterval is how often waitFor checks the state it waits for.
+var waitPollInterval = 5 * time.Second
+
+// waitFor calls check until it reports done or timeout passes. An error of
+// check ends the wait; checks report failures to read the state, which the
+// controller may not have right after a change, as status instead. The status
+// of the last check is part of the timeout error.
+func waitFor(what string, timeout time.Duration, check func() (done bool, status string, err error)) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		done, status, err := check()
+		if err != nil {
+			return fmt.Errorf("waiting for %v: %v", what, err)
+		}
+		if done {
+			return nil
+		}
+		if !time.Now().Add(waitPollInterval).Before(deadline) {
+			return fmt.Errorf("timed out after %v waiting for %v: %v", timeout, what, status)
+		}
+		log.Printf("[DEBUG] waitFor %v: %v\n", what, status)
+		time.Sleep(waitPollInterval)
+	}
+}
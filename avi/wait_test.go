@@ -0,0 +1,204 @@
+package avi
+
+import (
+	"encoding/json"
+	"github.com/avinetworks/sdk/go/models"
+	"github.com/hashicorp/terraform/helper/schema"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWaitForVirtualServiceOper(t *testing.T) {
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+	// the virtual service comes up on the third poll.
+	polls := 0
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/virtualservice/vs-1/runtime" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		polls++
+		status := map[string]interface{}{"state": "OPER_RESOURCES", "reason": []string{"Waiting for SE placement"}}
+		if polls >= 3 {
+			status = map[string]interface{}{"state": "OPER_UP"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"oper_status": status})
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviVirtualService().TestResourceData()
+	d.SetId("https://localhost/api/virtualservice/vs-1#web")
+	d.Set("wait_timeout", 600)
+	if err := waitForVirtualServiceOper(d, client); err != nil || polls != 0 {
+		t.Errorf("polls = %v, err = %v, expected no wait without wait_for_oper_up", polls, err)
+	}
+
+	d.Set("wait_for_oper_up", true)
+	if err := waitForVirtualServiceOper(d, client); err != nil || polls != 3 {
+		t.Errorf("polls = %v, err = %v, expected the virtual service to be up on the third poll", polls, err)
+	}
+
+	polls = 0
+	d.Set("wait_timeout", 0)
+	err := waitForVirtualServiceOper(d, client)
+	if err == nil || !strings.Contains(err.Error(), "OPER_RESOURCES, reason Waiting for SE placement") {
+		t.Errorf("err = %v, expected a timeout with the oper state reason", err)
+	}
+
+	polls = 0
+	d.Set("wait_oper_states", []interface{}{"OPER_RESOURCES"})
+	if err := waitForVirtualServiceOper(d, client); err != nil || polls != 1 {
+		t.Errorf("polls = %v, err = %v, expected OPER_RESOURCES to be accepted", polls, err)
+	}
+}
+
+func TestWaitForCloudReady(t *testing.T) {
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+	var states []string
+	var changed []int64
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/cloud/cloud-1/status" || len(states) == 0 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		status := map[string]interface{}{"state": states[0], "reason": "Discovering networks"}
+		if states[0] == "CLOUD_STATE_FAILED" {
+			status["reason"] = "Failed to connect to vCenter: invalid credentials"
+		}
+		if len(changed) > 0 {
+			status["last_changed_time"] = map[string]int64{"secs": changed[0], "usecs": 0}
+			changed = changed[1:]
+		}
+		if len(states) > 1 {
+			states = states[1:]
+		}
+		json.NewEncoder(w).Encode(status)
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviCloud().TestResourceData()
+	d.SetId("https://localhost/api/cloud/cloud-1#vcenter")
+	d.Set("wait_for_ready", true)
+	states = []string{"CLOUD_STATE_IN_PROGRESS", "CLOUD_STATE_IN_PROGRESS", "CLOUD_STATE_PLACEMENT_READY"}
+	if err := waitForCloudReady(d, client, time.Minute, nil); err != nil || len(states) != 1 {
+		t.Errorf("states = %v, err = %v, expected the cloud to be ready", states, err)
+	}
+
+	states = []string{"CLOUD_STATE_IN_PROGRESS", "CLOUD_STATE_FAILED"}
+	err := waitForCloudReady(d, client, time.Minute, nil)
+	if err == nil || !strings.Contains(err.Error(), "invalid credentials") {
+		t.Errorf("err = %v, expected the connector failure", err)
+	}
+
+	states = []string{"CLOUD_STATE_IN_PROGRESS"}
+	err = waitForCloudReady(d, client, 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "timed out") || !strings.Contains(err.Error(), "Discovering networks") {
+		t.Errorf("err = %v, expected a timeout with the cloud state reason", err)
+	}
+
+	// after an update the status from before the update is not accepted.
+	secs := int64(100)
+	before := &aviCloudStatus{State: "CLOUD_STATE_PLACEMENT_READY", LastChangedTime: &models.TimeStamp{Secs: &secs}}
+	states = []string{"CLOUD_STATE_PLACEMENT_READY", "CLOUD_STATE_PLACEMENT_READY", "CLOUD_STATE_PLACEMENT_READY"}
+	changed = []int64{100, 100, 200}
+	if err := waitForCloudReady(d, client, time.Minute, before); err != nil || len(changed) != 0 {
+		t.Errorf("changed = %v, err = %v, expected the cloud to be ready once its status changed", changed, err)
+	}
+
+	states = []string{"CLOUD_STATE_IN_PROGRESS", "CLOUD_STATE_PLACEMENT_READY"}
+	changed = nil
+	before = &aviCloudStatus{State: "CLOUD_STATE_PLACEMENT_READY"}
+	if err := waitForCloudReady(d, client, time.Minute, before); err != nil {
+		t.Errorf("err = %v, expected the cloud to be ready after a state change", err)
+	}
+
+	states = []string{"CLOUD_STATE_FAILED", "CLOUD_STATE_IN_PROGRESS", "CLOUD_STATE_PLACEMENT_READY"}
+	if err := waitForCloudReady(d, client, time.Minute, &aviCloudStatus{State: "CLOUD_STATE_FAILED"}); err != nil {
+		t.Errorf("err = %v, expected the failure from before the update to be ignored", err)
+	}
+
+	states = []string{"CLOUD_STATE_PLACEMENT_READY"}
+	err = waitForCloudReady(d, client, 0, before)
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, expected the unchanged status not to be accepted", err)
+	}
+}
+
+func TestWaitForClusterReady(t *testing.T) {
+	defer func(interval time.Duration) { waitPollInterval = interval }(waitPollInterval)
+	waitPollInterval = time.Millisecond
+	// the api is unavailable on the first poll, and the third node joins
+	// on the fourth.
+	polls := 0
+	client, server := newTestAviClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/cluster/runtime" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		polls++
+		if polls == 1 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		nodes := []map[string]string{
+			{"name": "10.10.0.1", "mgmt_ip": "10.10.0.1", "state": "CLUSTER_ACTIVE"},
+			{"name": "10.10.0.2", "mgmt_ip": "10.10.0.2", "state": "CLUSTER_ACTIVE"},
+			{"name": "10.10.0.3", "mgmt_ip": "10.10.0.3", "state": "CLUSTER_STARTING"},
+		}
+		state := map[string]string{"state": "CLUSTER_UP_HA_COMPROMISED", "reason": "Node 10.10.0.3 is joining"}
+		if polls >= 4 {
+			nodes[2]["state"] = "CLUSTER_ACTIVE"
+			state = map[string]string{"state": "CLUSTER_UP_HA_ACTIVE"}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"cluster_state": state, "node_states": nodes})
+	})
+	defer server.Close()
+	testProviderSettings(client, 0)
+
+	d := resourceAviCluster().TestResourceData()
+	var nodes []interface{}
+	for _, ip := range []string{"10.10.0.1", "10.10.0.2", "10.10.0.3"} {
+		nodes = append(nodes, map[string]interface{}{
+			"ip": schema.NewSet(schema.HashResource(ResourceIpAddrSchema()),
+				[]interface{}{map[string]interface{}{"addr": ip, "type": "V4"}}),
+		})
+	}
+	if err := d.Set("nodes", nodes); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := waitForClusterReady(d, client, time.Minute); err != nil || polls != 4 {
+		t.Errorf("polls = %v, err = %v, expected the cluster to be up on the fourth poll", polls, err)
+	}
+
+	polls = 1
+	err := waitForClusterReady(d, client, 0)
+	if err == nil || !strings.Contains(err.Error(), "Node 10.10.0.3 is joining, node 10.10.0.3 not active") {
+		t.Errorf("err = %v, expected a timeout with the joining node", err)
+	}
+
+	// the ready runtime of the previous nodes is not accepted for the
+	// remaining nodes.
+	polls = 3
+	if err := d.Set("nodes", nodes[:2]); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	err = waitForClusterReady(d, client, 0)
+	if err == nil || !strings.Contains(err.Error(), "node 10.10.0.3 not removed") {
+		t.Errorf("err = %v, expected a timeout with the removed node", err)
+	}
+
+	// without nodes, only the cluster state is waited for.
+	polls = 2
+	if err := d.Set("nodes", nil); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if err := waitForClusterReady(d, client, time.Minute); err != nil || polls != 3 {
+		t.Errorf("polls = %v, err = %v, expected the cluster to be up on the third poll", polls, err)
+	}
+}